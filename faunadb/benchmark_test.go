@@ -43,7 +43,7 @@ var (
 	benckmarkJSON = []byte(`
 	{
 		"Ref": {
-			"@ref": "classes/spells/42"
+			"@ref": {"id": "42", "collection": {"@ref": {"id": "spells", "collection": {"@ref": {"id": "collections"}}}}}
 		},
 		"Any": "any value",
 		"Date": { "@date": "1970-01-03" },
@@ -0,0 +1,249 @@
+package faunadb
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CostLimits are limits on the ops reported by FaunaDB for queries. A zero limit is not checked.
+type CostLimits struct {
+	ComputeOps   int64
+	ByteReadOps  int64
+	ByteWriteOps int64
+}
+
+// Budget describes the cost guardrails of a FaunaClient. See CostBudget.
+type Budget struct {
+	// PerQuery limits the ops of every query. MaxComputeOps and MaxByteReadOps override it for a single query.
+	PerQuery CostLimits
+
+	// PerTag limits the ops of the queries sharing the same value of the tag TagKey over a rolling Window.
+	// Queries without that tag are not limited. See Tag.
+	TagKey string
+	Window time.Duration // the window of a rolling window budget
+	PerTag CostLimits
+
+	// OnExceeded, if set, is called when a limit is crossed, instead of failing the query.
+	OnExceeded func(BudgetExceeded)
+}
+
+// BudgetExceeded is the error returned when a query crosses a cost limit. FaunaDB reports the ops of a query
+// once it has run: a query that crossed its own limits has been executed, including its writes.
+type BudgetExceeded struct {
+	Ops    string        // "compute", "byte read" or "byte write"
+	Limit  int64         // the limit crossed
+	Used   int64         // the ops used by the query, or by the tag within the window
+	Tag    string        // the "key=value" tag of a rolling window budget, empty for query limits
+	Window time.Duration // the window of a rolling window budget
+	Stats  QueryStats    // the stats of the query, zero if it was rejected before being sent
+}
+
+func (err BudgetExceeded) Error() string {
+	if err.Tag == "" {
+		return fmt.Sprintf("Query exceeded its %s ops budget: used %d of %d", err.Ops, err.Used, err.Limit)
+	}
+
+	return fmt.Sprintf("Tag %s exceeded its %s ops budget: used %d of %d in the last %s",
+		err.Tag, err.Ops, err.Used, err.Limit, err.Window)
+}
+
+// CostBudget configures cost guardrails for all queries of a FaunaClient. The ops reported by FaunaDB for
+// each query are checked against the per query limits and, for queries tagged with the budget TagKey, added
+// to the rolling window of the tag value. Once a tag has spent its window budget, its queries are rejected
+// with a BudgetExceeded error, without being sent, until older queries leave the window. Queries failed by
+// FaunaDB are charged the ops it reports for them. Clients created from this client share its budget.
+func CostBudget(budget Budget) ClientConfig {
+	return func(cli *FaunaClient) {
+		cli.budget = &costBudget{Budget: budget, usage: map[string][]costUsage{}, now: time.Now}
+	}
+}
+
+// MaxComputeOps fails the query with a BudgetExceeded error if FaunaDB reports more than max compute ops for it.
+func MaxComputeOps(max int64) QueryConfig {
+	return func(req *faunaRequest) { req.limits.ComputeOps = max }
+}
+
+// MaxByteReadOps fails the query with a BudgetExceeded error if FaunaDB reports more than max byte read ops for it.
+func MaxByteReadOps(max int64) QueryConfig {
+	return func(req *faunaRequest) { req.limits.ByteReadOps = max }
+}
+
+type costUsage struct {
+	time  time.Time
+	stats QueryStats
+}
+
+type costBudget struct {
+	Budget
+
+	mutex sync.Mutex
+	usage map[string][]costUsage // by tag value
+	swept time.Time              // the last time the usage of all tags was pruned
+	now   func() time.Time
+}
+
+// checkBudget checks the stats of a query response against the query limits and the client budget.
+func (client *FaunaClient) checkBudget(request *Request, limits CostLimits, response *Response) error {
+	if client.budget == nil && limits == (CostLimits{}) {
+		return nil
+	}
+
+	var err error
+	stats := parseQueryStats(response.Headers)
+
+	if client.budget != nil {
+		limits = limits.or(client.budget.PerQuery)
+		err = client.budget.charge(request, stats)
+	}
+
+	if exceeded, ok := limits.check(stats); ok {
+		exceeded.Stats = stats
+		return client.budget.exceeded(exceeded)
+	}
+
+	return err
+}
+
+// admit rejects a request whose tag has already spent its window budget.
+func (budget *costBudget) admit(request *Request) error {
+	tag, ok := budget.tag(request)
+	if !ok {
+		return nil
+	}
+
+	budget.mutex.Lock()
+	used := budget.used(tag, budget.now())
+	budget.mutex.Unlock()
+
+	if exceeded, ok := budget.PerTag.spent(used); ok {
+		exceeded.Tag = budget.TagKey + "=" + tag
+		exceeded.Window = budget.Window
+		return budget.exceeded(exceeded)
+	}
+
+	return nil
+}
+
+// charge adds the stats of a query to the window of its tag.
+func (budget *costBudget) charge(request *Request, stats QueryStats) error {
+	tag, ok := budget.tag(request)
+	if !ok {
+		return nil
+	}
+
+	now := budget.now()
+
+	budget.mutex.Lock()
+	budget.usage[tag] = append(budget.usage[tag], costUsage{now, stats})
+	used := budget.used(tag, now)
+	budget.sweep(now)
+	budget.mutex.Unlock()
+
+	if exceeded, ok := budget.PerTag.check(used); ok {
+		exceeded.Tag = budget.TagKey + "=" + tag
+		exceeded.Window = budget.Window
+		exceeded.Stats = stats
+		return budget.exceeded(exceeded)
+	}
+
+	return nil
+}
+
+// tag returns the value of the budget tag of the request, if the budget has window limits.
+func (budget *costBudget) tag(request *Request) (string, bool) {
+	if budget.TagKey == "" || budget.Window <= 0 || budget.PerTag == (CostLimits{}) {
+		return "", false
+	}
+
+	_, tags := parseTags(request.Headers)
+	tag, ok := tags[budget.TagKey]
+	return tag, ok
+}
+
+// used drops the usage of a tag older than the window and returns the remaining total. Must hold the mutex.
+func (budget *costBudget) used(tag string, now time.Time) (used QueryStats) {
+	usage := budget.usage[tag]
+
+	for len(usage) > 0 && now.Sub(usage[0].time) >= budget.Window {
+		usage = usage[1:]
+	}
+
+	if len(usage) == 0 {
+		delete(budget.usage, tag)
+		return
+	}
+	budget.usage[tag] = usage
+
+	for _, u := range usage {
+		used.ComputeOps += u.stats.ComputeOps
+		used.ByteReadOps += u.stats.ByteReadOps
+		used.ByteWriteOps += u.stats.ByteWriteOps
+	}
+
+	return
+}
+
+// sweep prunes the usage of all tags, at most once per window, so that tags no longer queried are dropped.
+// Must hold the mutex.
+func (budget *costBudget) sweep(now time.Time) {
+	if now.Sub(budget.swept) < budget.Window {
+		return
+	}
+
+	for tag := range budget.usage {
+		budget.used(tag, now)
+	}
+	budget.swept = now
+}
+
+// exceeded calls the budget callback, if any, or returns the error.
+func (budget *costBudget) exceeded(err BudgetExceeded) error {
+	if budget != nil && budget.OnExceeded != nil {
+		budget.OnExceeded(err)
+		return nil
+	}
+
+	return err
+}
+
+// or returns the limits, using the given defaults for the limits not set.
+func (limits CostLimits) or(defaults CostLimits) CostLimits {
+	if limits.ComputeOps == 0 {
+		limits.ComputeOps = defaults.ComputeOps
+	}
+	if limits.ByteReadOps == 0 {
+		limits.ByteReadOps = defaults.ByteReadOps
+	}
+	if limits.ByteWriteOps == 0 {
+		limits.ByteWriteOps = defaults.ByteWriteOps
+	}
+	return limits
+}
+
+// check returns the first limit exceeded by the stats.
+func (limits CostLimits) check(stats QueryStats) (BudgetExceeded, bool) {
+	return limits.find(stats, func(used, limit int64) bool { return used > limit })
+}
+
+// spent returns the first limit reached by the stats.
+func (limits CostLimits) spent(stats QueryStats) (BudgetExceeded, bool) {
+	return limits.find(stats, func(used, limit int64) bool { return used >= limit })
+}
+
+func (limits CostLimits) find(stats QueryStats, crossed func(used, limit int64) bool) (BudgetExceeded, bool) {
+	for _, ops := range []struct {
+		name        string
+		used, limit int64
+	}{
+		{"compute", stats.ComputeOps, limits.ComputeOps},
+		{"byte read", stats.ByteReadOps, limits.ByteReadOps},
+		{"byte write", stats.ByteWriteOps, limits.ByteWriteOps},
+	} {
+		if ops.limit > 0 && crossed(ops.used, ops.limit) {
+			return BudgetExceeded{Ops: ops.name, Limit: ops.limit, Used: ops.used}, true
+		}
+	}
+
+	return BudgetExceeded{}, false
+}
@@ -0,0 +1,169 @@
+package faunadb
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func opsHandler(computeOps, byteReadOps int64) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerComputeOps, strconv.FormatInt(computeOps, 10))
+		w.Header().Set(headerByteReadOps, strconv.FormatInt(byteReadOps, 10))
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}
+}
+
+func TestMaxComputeOps(t *testing.T) {
+	client, closeServer := mockedClient(opsHandler(120, 10))
+	defer closeServer()
+
+	value, err := client.Query(Add(40, 2), MaxComputeOps(200))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+
+	value, err = client.Query(Add(40, 2), MaxComputeOps(100))
+	require.Nil(t, value)
+	require.Equal(t, BudgetExceeded{
+		Ops:   "compute",
+		Limit: 100,
+		Used:  120,
+		Stats: QueryStats{ComputeOps: 120, ByteReadOps: 10},
+	}, err)
+	require.EqualError(t, err, "Query exceeded its compute ops budget: used 120 of 100")
+
+	value, stats, err := client.QueryWithStats(Add(40, 2), MaxComputeOps(100))
+	require.Nil(t, value)
+	require.IsType(t, BudgetExceeded{}, err)
+	require.Equal(t, QueryStats{ComputeOps: 120, ByteReadOps: 10}, stats)
+}
+
+func TestMaxByteReadOpsOverridesBudget(t *testing.T) {
+	client, closeServer := mockedClient(opsHandler(1, 10), CostBudget(Budget{
+		PerQuery: CostLimits{ComputeOps: 5, ByteReadOps: 5},
+	}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Equal(t, BudgetExceeded{Ops: "byte read", Limit: 5, Used: 10, Stats: QueryStats{ComputeOps: 1, ByteReadOps: 10}}, err)
+
+	_, err = client.Query(Add(40, 2), MaxByteReadOps(20))
+	require.NoError(t, err)
+}
+
+func TestCostBudgetCallback(t *testing.T) {
+	var exceeded []BudgetExceeded
+
+	client, closeServer := mockedClient(opsHandler(120, 10), CostBudget(Budget{
+		PerQuery:   CostLimits{ComputeOps: 100},
+		OnExceeded: func(err BudgetExceeded) { exceeded = append(exceeded, err) },
+	}))
+	defer closeServer()
+
+	value, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+	require.Len(t, exceeded, 1)
+	require.Equal(t, int64(120), exceeded[0].Used)
+}
+
+// fakeClock replaces the clock of the client budget, so that tests control the rolling window.
+func fakeClock(client *FaunaClient) *time.Time {
+	now := time.Unix(0, 0)
+	client.budget.now = func() time.Time { return now }
+	return &now
+}
+
+func TestCostBudgetPerTag(t *testing.T) {
+	requests := 0
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		opsHandler(60, 1)(w, r)
+	}, CostBudget(Budget{
+		TagKey: "tenant",
+		Window: time.Minute,
+		PerTag: CostLimits{ComputeOps: 100},
+	}))
+	defer closeServer()
+
+	now := fakeClock(client)
+
+	_, err := client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.NoError(t, err)
+
+	*now = now.Add(30 * time.Second)
+
+	_, err = client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.Equal(t, BudgetExceeded{
+		Ops:    "compute",
+		Limit:  100,
+		Used:   120,
+		Tag:    "tenant=acme",
+		Window: time.Minute,
+		Stats:  QueryStats{ComputeOps: 60, ByteReadOps: 1},
+	}, err)
+
+	_, err = client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.Equal(t, BudgetExceeded{Ops: "compute", Limit: 100, Used: 120, Tag: "tenant=acme", Window: time.Minute}, err)
+	require.Equal(t, 2, requests)
+
+	_, err = client.Query(Add(40, 2), Tag("tenant", "other"))
+	require.NoError(t, err)
+	_, err = client.Query(Add(40, 2))
+	require.NoError(t, err)
+
+	*now = now.Add(time.Minute)
+
+	_, err = client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.NoError(t, err)
+	require.Equal(t, 5, requests)
+}
+
+func TestCostBudgetChargesFailedQueries(t *testing.T) {
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerComputeOps, "150")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "invalid argument", "description": "Invalid"}]}`))
+	}, CostBudget(Budget{
+		TagKey: "tenant",
+		Window: time.Minute,
+		PerTag: CostLimits{ComputeOps: 100},
+	}))
+	defer closeServer()
+
+	fakeClock(client)
+
+	_, err := client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.IsType(t, BadRequest{}, err)
+
+	_, err = client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.Equal(t, BudgetExceeded{Ops: "compute", Limit: 100, Used: 150, Tag: "tenant=acme", Window: time.Minute}, err)
+}
+
+func TestCostBudgetPrunesIdleTags(t *testing.T) {
+	client, closeServer := mockedClient(opsHandler(1, 1), CostBudget(Budget{
+		TagKey: "tenant",
+		Window: time.Minute,
+		PerTag: CostLimits{ComputeOps: 100},
+	}))
+	defer closeServer()
+
+	now := fakeClock(client)
+
+	for _, tenant := range []string{"a", "b", "c"} {
+		_, err := client.Query(Add(40, 2), Tag("tenant", tenant))
+		require.NoError(t, err)
+	}
+	require.Len(t, client.budget.usage, 3)
+
+	*now = now.Add(time.Minute)
+
+	_, err := client.Query(Add(40, 2), Tag("tenant", "d"))
+	require.NoError(t, err)
+	require.Len(t, client.budget.usage, 1)
+	require.Contains(t, client.budget.usage, "d")
+}
@@ -30,6 +30,16 @@ const (
 	headerLastSeenTxn = "X-Last-Seen-Txn"
 	headerTraceparent = "Traceparent"
 	headerTags        = "X-Fauna-Tags"
+
+	headerComputeOps        = "X-Compute-Ops"
+	headerByteReadOps       = "X-Byte-Read-Ops"
+	headerByteWriteOps      = "X-Byte-Write-Ops"
+	headerQueryTime         = "X-Query-Time"
+	headerStorageBytesRead  = "X-Storage-Bytes-Read"
+	headerStorageBytesWrite = "X-Storage-Bytes-Write"
+	headerTxnRetries        = "X-Txn-Retries"
+	headerQueryBytesIn      = "X-Query-Bytes-In"
+	headerQueryBytesOut     = "X-Query-Bytes-Out"
 )
 
 var resource = ObjKey("resource")
@@ -103,12 +113,14 @@ func Tag(key string, value string) QueryConfig {
 
 type faunaRequest struct {
 	headers map[string]string
+	limits  CostLimits
 }
 
 type faunaResponse struct {
 	response *http.Response
 	ctx      context.Context
 	cncl     context.CancelFunc
+	attempts int
 }
 
 // ObserverCallback is the callback type for requests.
@@ -135,6 +147,12 @@ type FaunaClient struct {
 	queryTimeoutMs   uint64
 	observer         ObserverCallback
 	headers          map[string]string
+	retry            RetryOptions
+	middlewares      []MiddlewareFunc
+	tracer           Tracer
+	traceFQL         bool
+	metrics          *metricsCollector
+	budget           *costBudget
 }
 
 // QueryResult is a structure containing the result context for a given FaunaDB query.
@@ -148,6 +166,8 @@ type QueryResult struct {
 	Headers    map[string][]string
 	StartTime  time.Time
 	EndTime    time.Time
+	Attempts   int
+	Stats      QueryStats
 }
 
 /*
@@ -262,9 +282,15 @@ func getRuntimeEnvironment() string {
 
 // QueryResult run and return the cost headers associated with this query.
 func (client *FaunaClient) QueryResult(expr Expr) (value Value, headers map[string][]string, err error) {
+	return client.QueryResultContext(context.Background(), expr)
+}
+
+// QueryResultContext run and return the cost headers associated with this query. The provided context
+// is combined with the client's query timeout and may be used to cancel the request.
+func (client *FaunaClient) QueryResultContext(ctx context.Context, expr Expr) (value Value, headers map[string][]string, err error) {
 	value, err = client.NewWithObserver(func(queryResult *QueryResult) {
 		headers = queryResult.Headers
-	}).Query(expr)
+	}).QueryContext(ctx, expr)
 
 	return
 }
@@ -280,15 +306,71 @@ func (client *FaunaClient) BatchQueryResult(expr []Expr) (value []Value, headers
 
 // Query is the primary method used to send a query language expression to FaunaDB.
 func (client *FaunaClient) Query(expr Expr, configs ...QueryConfig) (value Value, err error) {
+	return client.QueryContext(context.Background(), expr, configs...)
+}
+
+// QueryContext sends a query language expression to FaunaDB using the provided context.
+// The context is combined with the client's query timeout: whichever expires first aborts the request.
+// When the request is aborted because of the context, the returned error is either
+// context.Canceled or context.DeadlineExceeded.
+func (client *FaunaClient) QueryContext(ctx context.Context, expr Expr, configs ...QueryConfig) (value Value, err error) {
+	return client.query(ctx, SpanQuery, expr, configs)
+}
+
+func (client *FaunaClient) query(ctx context.Context, spanName string, expr Expr, configs []QueryConfig) (value Value, err error) {
+	var response *Response
+
+	if response, err = client.queryResponse(ctx, spanName, expr, configs); err == nil && response != nil {
+		value = response.Value
+	}
+
+	return
+}
+
+func (client *FaunaClient) queryResponse(ctx context.Context, spanName string, expr Expr, configs []QueryConfig) (response *Response, err error) {
+	headers, limits := client.requestConfig(configs)
+	request := &Request{Expr: expr, Headers: headers}
+
+	ctx, span := client.startSpan(ctx, spanName, request)
+	defer span.End()
+
+	if client.budget != nil {
+		if err = client.budget.admit(request); err != nil {
+			span.RecordError(err)
+			return
+		}
+	}
+
+	start := time.Now()
+	response, err = client.roundTrip(ctx, request, client.sendQuery)
+	recordResponse(span, response, err)
+
+	if client.metrics != nil {
+		client.metrics.record(request, response, err, time.Since(start))
+	}
+
+	if response != nil {
+		// Failed queries are charged too, but keep their own error
+		if budgetErr := client.checkBudget(request, limits, response); budgetErr != nil && err == nil {
+			err = budgetErr
+			span.RecordError(err)
+			// Keep the stats of the rejected query, but not its result
+			response = &Response{StatusCode: response.StatusCode, Headers: response.Headers, attempts: response.attempts}
+		}
+	}
+
+	return
+}
+
+// sendQuery is the last RoundTrip of queries, sending the request to FaunaDB.
+func (client *FaunaClient) sendQuery(ctx context.Context, request *Request) (result *Response, err error) {
 	var response faunaResponse
 	var payload []byte
 
 	startTime := time.Now()
 
-	if payload, err = client.prepareRequestBody(expr); err == nil {
-		body := bytes.NewReader(payload)
-
-		response, err = client.performRequest(body, client.endpoint, false, configs)
+	if payload, err = client.prepareRequestBody(request.Expr); err == nil {
+		response, err = client.performRequest(ctx, payload, client.endpoint, false, request.Headers)
 
 		httpResponse := response.response
 
@@ -298,14 +380,25 @@ func (client *FaunaClient) Query(expr Expr, configs ...QueryConfig) (value Value
 				_ = httpResponse.Body.Close()
 				response.cncl()
 			}()
+		} else if response.cncl != nil {
+			defer response.cncl()
 		}
 
 		if err == nil {
-			if err = checkForResponseErrors(httpResponse); err == nil {
-				value, err = client.parseResponse(httpResponse, expr, false, startTime)
+			var value Value
+			if value, err = client.parseResponse(httpResponse, request.Expr, false, startTime, response.attempts); err == nil {
+				result = &Response{StatusCode: httpResponse.StatusCode, Headers: httpResponse.Header, Value: value, attempts: response.attempts}
 			}
 		}
 
+		if result == nil && httpResponse != nil {
+			// The headers of error responses report the cost of failed queries
+			result = &Response{StatusCode: httpResponse.StatusCode, Headers: httpResponse.Header, attempts: response.attempts}
+		}
+
+		if err != nil && response.ctx != nil && response.ctx.Err() != nil {
+			err = response.ctx.Err()
+		}
 	}
 
 	return
@@ -314,6 +407,12 @@ func (client *FaunaClient) Query(expr Expr, configs ...QueryConfig) (value Value
 // BatchQuery will sends multiple simultaneous queries to FaunaDB. values are returned in the same order
 // as the queries.
 func (client *FaunaClient) BatchQuery(exprs []Expr) (values []Value, err error) {
+	return client.BatchQueryContext(context.Background(), exprs)
+}
+
+// BatchQueryContext will sends multiple simultaneous queries to FaunaDB using the provided context.
+// values are returned in the same order as the queries.
+func (client *FaunaClient) BatchQueryContext(ctx context.Context, exprs []Expr) (values []Value, err error) {
 	arr := make(unescapedArr, len(exprs))
 
 	for i, expr := range exprs {
@@ -322,7 +421,7 @@ func (client *FaunaClient) BatchQuery(exprs []Expr) (values []Value, err error)
 
 	var res Value
 
-	if res, err = client.Query(arr); err == nil {
+	if res, err = client.query(ctx, SpanBatchQuery, arr, nil); err == nil {
 		err = res.Get(&values)
 	}
 
@@ -350,15 +449,22 @@ func (client *FaunaClient) Stream(query Expr, config ...StreamConfig) StreamSubs
 }
 
 func (client *FaunaClient) startStream(subscription *StreamSubscription) (err error) {
-	var payload []byte
 	var response faunaResponse
 
-	startTime := time.Now()
-	payload, err = client.prepareRequestBody(subscription.query)
-	if err != nil {
-		return
+	subscription.ctx, subscription.span = client.startSpan(subscription.ctx, SpanStream, &Request{Expr: subscription.query})
+
+	if response, err = client.connectStream(subscription, 0); err == nil {
+		go client.readStream(subscription, response)
+	} else {
+		subscription.span.SetAttribute(AttributeStreamStatus, streamStatusName(StreamConnError))
+		subscription.span.End()
 	}
-	body := ioutil.NopCloser(bytes.NewReader(payload))
+
+	return
+}
+
+func (client *FaunaClient) connectStream(subscription *StreamSubscription, lastSeenTxn int64) (response faunaResponse, err error) {
+	var configs []QueryConfig
 
 	var endpoint strings.Builder
 	endpoint.WriteString(client.streamEndpoint)
@@ -374,59 +480,276 @@ func (client *FaunaClient) startStream(subscription *StreamSubscription) (err er
 		}
 	}
 
-	response, err = client.performRequest(body, endpoint.String(), true, nil)
-	if err != nil {
+	if lastSeenTxn > 0 {
+		configs = append(configs, lastSeenTxnHeader(lastSeenTxn))
+	}
+
+	send := func(ctx context.Context, request *Request) (*Response, error) {
+		return client.sendStream(ctx, request, endpoint.String())
+	}
+
+	headers, _ := client.requestConfig(configs)
+	request := &Request{Expr: subscription.query, Headers: headers, Streaming: true}
+	injectTraceparent(request.Headers, subscription.span)
+
+	var result *Response
+	if result, err = client.roundTrip(subscription.ctx, request, send); err == nil {
+		if result == nil || result.stream == nil {
+			err = errStreamResponse
+		} else {
+			response = *result.stream
+		}
+	}
+	recordResponse(subscription.span, result, err)
+
+	if err != nil && subscription.ctx.Err() != nil {
+		err = subscription.ctx.Err()
+	}
+
+	return
+}
+
+// sendStream is the last RoundTrip of stream subscriptions, opening the stream connection.
+func (client *FaunaClient) sendStream(ctx context.Context, request *Request, endpoint string) (result *Response, err error) {
+	var payload []byte
+	var response faunaResponse
+
+	if payload, err = client.prepareRequestBody(request.Expr); err != nil {
 		return
 	}
 
+	response, err = client.performRequest(ctx, payload, endpoint, true, request.Headers)
+
 	httpResponse := response.response
-	_ = client.storeLastTxnTime(httpResponse.Header)
-	if err = checkForResponseErrors(httpResponse); err != nil {
-		httpResponse.Body.Close()
+	if httpResponse != nil {
+		_ = client.storeLastTxnTime(httpResponse.Header)
+	}
+	if err != nil {
+		if response.ctx.Err() != nil {
+			err = response.ctx.Err()
+		}
+		if httpResponse != nil {
+			httpResponse.Body.Close()
+		}
 		response.cncl()
 		return
 	}
 
-	go func() {
-		<-subscription.closed
-		httpResponse.Body.Close()
+	result = &Response{StatusCode: httpResponse.StatusCode, Headers: httpResponse.Header, stream: &response}
+	return
+}
+
+// readStream dispatches the events of the subscription until it is closed, reconnecting if configured to.
+func (client *FaunaClient) readStream(subscription *StreamSubscription, response faunaResponse) {
+	var reconnect *ReconnectEvent
+	var ok bool
+
+	for {
+		cause := client.readStreamConnection(subscription, response, reconnect)
+		response.response.Body.Close()
 		response.cncl()
-	}()
 
-	go func() {
+		if cause == ErrStreamIdle && subscription.config.Reconnect == nil {
+			subscription.finish(StreamConnError)
+			return
+		}
 
-		for {
-			var obj Obj
+		if cause == nil || subscription.config.Reconnect == nil {
+			subscription.finish(StreamConnClosed)
+			return
+		}
 
-			if val, err := client.parseResponse(httpResponse, subscription.query, true, startTime); err != nil {
-				if err == io.EOF || err.Error() == "http2: response body closed" {
-					subscription.Close()
-					break
+		if response, reconnect, ok = client.reconnectStream(subscription, cause); !ok {
+			subscription.finish(StreamConnError)
+			return
+		}
+	}
+}
+
+// readStreamConnection dispatches the events received on a single connection. It returns the error that ended
+// the connection, or nil if the stream must not be resumed.
+func (client *FaunaClient) readStreamConnection(subscription *StreamSubscription, response faunaResponse, reconnect *ReconnectEvent) error {
+	startTime := time.Now()
+	resumeTxn := subscription.lastSeenTxn()
+	terminated := false
+
+	// Events up to this txn were already delivered
+	var skipTxn int64
+	if reconnect != nil {
+		skipTxn = resumeTxn
+	}
+
+	var body io.Reader = response.response.Body
+	var idle *idleReader
+	if timeout := subscription.config.IdleTimeout; timeout > 0 {
+		idle = newIdleReader(body, timeout, response.cncl)
+		defer idle.Stop()
+		body = idle
+	}
+
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	parser := jsonParser{decoder}
+
+	for {
+		var obj Obj
+		var event StreamEvent
+
+		val, err := parser.parseNext()
+		if err != nil {
+			if subscription.ctx.Err() != nil || terminated {
+				return nil
+			}
+			if idle != nil && idle.Expired() {
+				if !subscription.emit(ErrorEvent{err: ErrStreamIdle}) {
+					return nil
 				}
-				subscription.events <- ErrorEvent{
-					err: err,
+				return ErrStreamIdle
+			}
+			if err != io.EOF && err.Error() != "http2: response body closed" {
+				if !subscription.emit(ErrorEvent{err: err}) {
+					return nil
 				}
-			} else {
-				if err = val.Get(&obj); err == nil {
-					var event StreamEvent
-					if event, err = unMarshalStreamEvent(obj); err == nil {
-						client.SyncLastTxnTime(event.Txn())
-						subscription.events <- event
-					} else {
-						subscription.events <- ErrorEvent{
-							err: err,
-						}
-					}
-				} else {
-					subscription.events <- ErrorEvent{
-						err: err,
-					}
+			}
+			return err
+		}
+
+		client.callObserver(response.response, subscription.query, true, val, startTime, response.attempts)
+
+		if err = val.Get(&obj); err == nil {
+			event, err = unMarshalStreamEvent(obj)
+		}
+
+		switch {
+		case err != nil:
+			event = ErrorEvent{err: err}
+		case event == nil:
+			continue
+		case event.Type() == StartEventT:
+			startTxn := event.Txn()
+			if reconnect != nil {
+				reconnect.txn = startTxn
+				event = *reconnect
+			}
+
+			client.SyncLastTxnTime(startTxn)
+			if !subscription.emit(event) {
+				return nil
+			}
+
+			// Reconnected streams replay the events missed while disconnected
+			replayFrom := subscription.config.ReplayFrom
+			if reconnect != nil && resumeTxn > 0 {
+				replayFrom = &resumeTxn
+			}
+
+			if replayFrom != nil {
+				var ok bool
+				if skipTxn, ok = client.replayStream(subscription, *replayFrom, startTxn); !ok {
+					return nil
 				}
 			}
+			continue
+		case event.Txn() <= skipTxn:
+			continue
+		case event.Type() == ErrorEventT:
+			terminated = true
 		}
-	}()
 
-	return
+		client.SyncLastTxnTime(event.Txn())
+
+		if !subscription.emit(event) {
+			return nil
+		}
+	}
+}
+
+// reconnectStream re-issues the stream request with backoff until it succeeds, the subscription is closed
+// or the reconnect attempts are exhausted.
+func (client *FaunaClient) reconnectStream(subscription *StreamSubscription, cause error) (faunaResponse, *ReconnectEvent, bool) {
+	opts := subscription.config.Reconnect
+
+	if !subscription.setStatus(StreamConnIdle) {
+		return faunaResponse{}, nil, false
+	}
+
+	err := cause
+
+	for attempt := 1; opts.MaxAttempts <= 0 || attempt <= opts.MaxAttempts; attempt++ {
+		if !waitForRetry(subscription.ctx, opts.Backoff.Delay(attempt)) {
+			return faunaResponse{}, nil, false
+		}
+
+		var response faunaResponse
+		if response, err = client.connectStream(subscription, subscription.lastSeenTxn()); err == nil {
+			if !subscription.setStatus(StreamConnActive) {
+				response.response.Body.Close()
+				response.cncl()
+				return faunaResponse{}, nil, false
+			}
+
+			subscription.reconnects++
+			subscription.span.SetAttribute(AttributeStreamReconnect, subscription.reconnects)
+
+			return response, &ReconnectEvent{attempts: attempt, cause: cause}, true
+		}
+
+		if subscription.ctx.Err() != nil || !isReconnectable(err) {
+			break
+		}
+	}
+
+	subscription.emit(ErrorEvent{err: err})
+	return faunaResponse{}, nil, false
+}
+
+// isReconnectable returns false for request errors that would fail again, such as an invalid secret.
+func isReconnectable(err error) bool {
+	if faunaErr, ok := err.(FaunaError); ok {
+		return IsRetryable(faunaErr) || faunaErr.Status() >= 500
+	}
+
+	return true
+}
+
+// idleReader cancels a stream connection when a read waits longer than the timeout for data.
+type idleReader struct {
+	reader  io.Reader
+	timeout time.Duration
+	timer   *time.Timer
+	expired int32
+}
+
+func newIdleReader(reader io.Reader, timeout time.Duration, cancel context.CancelFunc) *idleReader {
+	idle := &idleReader{reader: reader, timeout: timeout}
+	idle.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&idle.expired, 1)
+		cancel()
+	})
+	idle.timer.Stop()
+	return idle
+}
+
+func (idle *idleReader) Read(p []byte) (int, error) {
+	idle.timer.Reset(idle.timeout)
+	defer idle.timer.Stop()
+	return idle.reader.Read(p)
+}
+
+// Expired returns true if the connection was canceled for being idle.
+func (idle *idleReader) Expired() bool {
+	return atomic.LoadInt32(&idle.expired) == 1
+}
+
+// Stop disarms the idle timer.
+func (idle *idleReader) Stop() {
+	idle.timer.Stop()
+}
+
+func lastSeenTxnHeader(txn int64) QueryConfig {
+	return func(req *faunaRequest) {
+		req.headers[headerLastSeenTxn] = strconv.FormatInt(txn, 10)
+	}
 }
 
 // GetLastTxnTime gets the freshest timestamp reported to this client.
@@ -466,23 +789,47 @@ func (client *FaunaClient) newClient(basicAuth string, observer ObserverCallback
 		queryTimeoutMs:   client.queryTimeoutMs,
 		lastTxnTime:      client.lastTxnTime,
 		observer:         observer,
+		retry:            client.retry,
+		middlewares:      client.middlewares,
+		tracer:           client.tracer,
+		traceFQL:         client.traceFQL,
+		metrics:          client.metrics,
+		budget:           client.budget,
 	}
 }
 
-func (client *FaunaClient) performRequest(body io.Reader, endpoint string, streaming bool, configs []QueryConfig) (response faunaResponse, err error) {
-	var request *http.Request
+func (client *FaunaClient) performRequest(ctx context.Context, payload []byte, endpoint string, streaming bool, headers http.Header) (response faunaResponse, err error) {
 	var timeout = time.Duration(client.queryTimeoutMs) * time.Millisecond
 	if streaming {
-		response.ctx, response.cncl = context.WithCancel(context.Background())
+		response.ctx, response.cncl = context.WithCancel(ctx)
 
 	} else {
-		response.ctx, response.cncl = context.WithTimeout(context.Background(), timeout)
-	}
-	if request, err = client.prepareRequest(response.ctx, body, endpoint, configs); err == nil {
-		response.response, err = client.http.Do(request)
+		response.ctx, response.cncl = context.WithTimeout(ctx, timeout)
 	}
 
-	return
+	for response.attempts = 1; ; response.attempts++ {
+		var request *http.Request
+		var body io.Reader = bytes.NewReader(payload)
+		if streaming {
+			body = ioutil.NopCloser(body)
+		}
+
+		if request, err = client.prepareRequest(response.ctx, body, endpoint, headers); err != nil {
+			return
+		}
+		if response.response, err = client.http.Do(request); err != nil {
+			return
+		}
+		if err = checkForResponseErrors(response.response); err == nil || !client.retry.shouldRetry(err, response.attempts) {
+			return
+		}
+		if !waitForRetry(response.ctx, client.retry.Backoff.Delay(response.attempts)) {
+			return
+		}
+
+		_, _ = io.Copy(ioutil.Discard, response.response.Body)
+		_ = response.response.Body.Close()
+	}
 }
 
 func (client *FaunaClient) prepareRequestBody(expr Expr) (payload []byte, err error) {
@@ -490,32 +837,42 @@ func (client *FaunaClient) prepareRequestBody(expr Expr) (payload []byte, err er
 	return
 }
 
-func (client *FaunaClient) prepareRequest(ctx context.Context, body io.Reader, endpoint string, configs []QueryConfig) (request *http.Request, err error) {
-	if request, err = http.NewRequestWithContext(ctx, "POST", endpoint, body); err == nil {
-		request.Header.Add("Authorization", client.basicAuth)
-		for k, v := range client.headers {
-			request.Header.Add(k, v)
-		}
+// requestConfig returns the headers and the cost limits of a request issued with the given query configs.
+func (client *FaunaClient) requestConfig(configs []QueryConfig) (http.Header, CostLimits) {
+	var limits CostLimits
 
-		if len(configs) > 0 {
-			req := &faunaRequest{
-				headers: map[string]string{},
-			}
-			for _, config := range configs {
-				config(req)
-			}
-			for k, v := range req.headers {
-				request.Header.Add(k, v)
-			}
+	headers := make(http.Header, len(client.headers)+1)
+	headers.Add("Authorization", client.basicAuth)
+	for k, v := range client.headers {
+		headers.Add(k, v)
+	}
+
+	if len(configs) > 0 {
+		req := &faunaRequest{
+			headers: map[string]string{},
+		}
+		for _, config := range configs {
+			config(req)
+		}
+		for k, v := range req.headers {
+			headers.Add(k, v)
 		}
+		limits = req.limits
+	}
+
+	return headers, limits
+}
 
+func (client *FaunaClient) prepareRequest(ctx context.Context, body io.Reader, endpoint string, headers http.Header) (request *http.Request, err error) {
+	if request, err = http.NewRequestWithContext(ctx, "POST", endpoint, body); err == nil {
+		request.Header = headers.Clone()
 		client.addLastTxnTimeHeader(request)
 	}
 
 	return
 }
 
-func (client *FaunaClient) parseResponse(response *http.Response, expr Expr, streaming bool, startTime time.Time) (value Value, err error) {
+func (client *FaunaClient) parseResponse(response *http.Response, expr Expr, streaming bool, startTime time.Time, attempts int) (value Value, err error) {
 	var parsedResponse Value
 
 	if !streaming {
@@ -530,7 +887,7 @@ func (client *FaunaClient) parseResponse(response *http.Response, expr Expr, str
 		} else {
 			value, err = parsedResponse.At(resource).GetValue()
 		}
-		client.callObserver(response, expr, streaming, value, startTime)
+		client.callObserver(response, expr, streaming, value, startTime, attempts)
 	} else {
 		return nil, err
 	}
@@ -538,7 +895,7 @@ func (client *FaunaClient) parseResponse(response *http.Response, expr Expr, str
 	return
 }
 
-func (client *FaunaClient) callObserver(response *http.Response, expr Expr, streaming bool, value Value, startTime time.Time) {
+func (client *FaunaClient) callObserver(response *http.Response, expr Expr, streaming bool, value Value, startTime time.Time, attempts int) {
 	var event StreamEvent
 	if streaming {
 		var obj Obj
@@ -562,13 +919,15 @@ func (client *FaunaClient) callObserver(response *http.Response, expr Expr, stre
 		response.Header,
 		startTime,
 		time.Now(),
+		attempts,
+		parseQueryStats(response.Header),
 	}
 
 	client.observer(queryResult)
 }
 
 func (client *FaunaClient) addLastTxnTimeHeader(request *http.Request) {
-	if client.isTxnTimeEnabled {
+	if client.isTxnTimeEnabled && request.Header.Get(headerLastSeenTxn) == "" {
 		if lastSeen := atomic.LoadInt64(&client.lastTxnTime); lastSeen != 0 {
 			request.Header.Add(headerLastSeenTxn, strconv.FormatInt(lastSeen, 10))
 		}
@@ -0,0 +1,91 @@
+package faunadb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func mockedClient(handler http.HandlerFunc, configs ...ClientConfig) (*FaunaClient, func()) {
+	server := httptest.NewServer(handler)
+	configs = append([]ClientConfig{Endpoint(server.URL), HTTP(server.Client())}, configs...)
+
+	return NewFaunaClient("secret", configs...), server.Close
+}
+
+func slowHandler(delay time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(delay):
+			_, _ = w.Write([]byte(`{"resource": 42}`))
+		}
+	}
+}
+
+func TestQueryContext(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(0))
+	defer closeServer()
+
+	value, err := client.QueryContext(context.Background(), Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+}
+
+func TestQueryContextCanceled(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(200 * time.Millisecond))
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := client.QueryContext(ctx, Add(40, 2))
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestQueryContextDeadlineExceeded(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(200 * time.Millisecond))
+	defer closeServer()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.QueryContext(ctx, Add(40, 2))
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestQueryContextRespectsQueryTimeout(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(200*time.Millisecond), QueryTimeoutMS(20))
+	defer closeServer()
+
+	_, err := client.QueryContext(context.Background(), Add(40, 2))
+	require.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestBatchQueryContextCanceled(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(200 * time.Millisecond))
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.BatchQueryContext(ctx, []Expr{Add(1, 2), Add(3, 4)})
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestQueryResultContext(t *testing.T) {
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Compute-Ops", "1")
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	})
+	defer closeServer()
+
+	value, headers, err := client.QueryResultContext(context.Background(), Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+	require.Equal(t, []string{"1"}, headers["X-Compute-Ops"])
+}
@@ -27,6 +27,12 @@ func (d DecodeError) Error() string {
 	return fmt.Sprintf("Error while decoding fauna value at: %s. %s", path, err)
 }
 
+// FaunaUnmarshaler is implemented by types that can decode themselves from a FaunaDB value. It is honored
+// by Value.Get at every nesting level, including struct fields, map values and array elements.
+type FaunaUnmarshaler interface {
+	UnmarshalFauna(Value) error
+}
+
 type valueDecoder struct {
 	target     reflect.Value
 	targetType reflect.Type
@@ -42,6 +48,12 @@ func newValueDecoder(source interface{}) *valueDecoder {
 }
 
 func (c *valueDecoder) assign(value interface{}) error {
+	if v, ok := value.(Value); ok {
+		if unmarshaled, err := c.unmarshal(v); unmarshaled {
+			return err
+		}
+	}
+
 	source, sourceType := indirectValue(value)
 
 	if sourceType.AssignableTo(c.targetType) {
@@ -59,7 +71,20 @@ func (c *valueDecoder) assign(value interface{}) error {
 	}
 }
 
+// unmarshal decodes the value with UnmarshalFauna if the target implements FaunaUnmarshaler.
+func (c *valueDecoder) unmarshal(value Value) (bool, error) {
+	if unmarshaler, ok := unmarshalerOf(c.target); ok {
+		return true, unmarshaler.UnmarshalFauna(value)
+	}
+
+	return false, nil
+}
+
 func (c *valueDecoder) decodeArray(arr ArrayV) error {
+	if unmarshaled, err := c.unmarshal(arr); unmarshaled {
+		return err
+	}
+
 	if err := c.assign(arr); err == nil {
 		return nil
 	}
@@ -84,6 +109,10 @@ func (c *valueDecoder) makeNewSlice(arr []Value) error {
 }
 
 func (c *valueDecoder) decodeMap(obj ObjectV) error {
+	if unmarshaled, err := c.unmarshal(obj); unmarshaled {
+		return err
+	}
+
 	if err := c.assign(obj); err == nil {
 		return nil
 	}
@@ -117,29 +146,113 @@ func (c *valueDecoder) makeNewMap(obj map[string]Value) error {
 
 func (c *valueDecoder) fillStructFields(obj map[string]Value) (err error) {
 	newStruct := reflect.New(c.targetType).Elem()
-	aStructType := newStruct.Type()
-
-	//need to get all StructFields and unpack json values into the fields to check if they are empty - use allStructFields rather than
-	for key, field := range allStructFields(newStruct) {
-		f, _ := aStructType.FieldByName(key)
-		if !field.CanInterface() {
-			continue
-		}
-		fieldName, ignored, _, _ := parseTag(f) //need to parse tags
+	fields, err := cachedTypeFields(c.targetType)
+	if err != nil {
+		return DecodeError{err: err}
+	}
 
-		value, found := obj[fieldName]
+	var decoded map[string]bool
+	if fields.inline != nil {
+		decoded = make(map[string]bool, len(fields.list))
+	}
 
+	for _, f := range fields.list {
+		value, found := obj[f.name]
 		if !found {
 			continue
 		}
 
-		if ignored {
+		field, ok := fieldByIndex(newStruct, f.index, true)
+		if !ok || !field.CanSet() {
 			continue
 		}
 
 		if err = value.Get(field); err != nil {
-			return DecodeError{path: pathFromKeys(key), err: err}
+			return DecodeError{path: pathFromKeys(f.goName), err: err}
+		}
+
+		if decoded != nil {
+			decoded[f.name] = true
 		}
 	}
+
+	if fields.inline != nil {
+		if err = fillInlineMap(newStruct, fields.inline, obj, decoded); err != nil {
+			return
+		}
+	}
+
 	return c.assign(newStruct)
 }
+
+// fillInlineMap decodes the object keys that do not match any struct field into the inline map.
+func fillInlineMap(aStruct reflect.Value, inline *structField, obj map[string]Value, decoded map[string]bool) error {
+	field, ok := fieldByIndex(aStruct, inline.index, true)
+	if !ok || !field.CanSet() {
+		return nil
+	}
+
+	elemType := inline.typ.Elem()
+
+	for key, value := range obj {
+		if decoded[key] {
+			continue
+		}
+
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(inline.typ))
+		}
+
+		newElem := reflect.New(elemType).Elem()
+		if err := value.Get(newElem); err != nil {
+			return DecodeError{path: pathFromKeys(key), err: err}
+		}
+
+		field.SetMapIndex(reflect.ValueOf(key).Convert(inline.typ.Key()), newElem)
+	}
+
+	return nil
+}
+
+// decodeNull leaves the target untouched, so nil pointers stay nil, unless it implements FaunaUnmarshaler.
+func decodeNull(i interface{}) error {
+	value, ok := i.(reflect.Value)
+	if !ok {
+		value = reflect.ValueOf(i)
+	}
+
+	for value.IsValid() {
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil
+		}
+
+		if unmarshaler, ok := unmarshalerOf(value); ok {
+			return unmarshaler.UnmarshalFauna(NullV{})
+		}
+
+		if value.Kind() != reflect.Ptr {
+			break
+		}
+
+		value = value.Elem()
+	}
+
+	return nil
+}
+
+func unmarshalerOf(target reflect.Value) (FaunaUnmarshaler, bool) {
+	if !target.IsValid() || !target.CanInterface() {
+		return nil, false
+	}
+
+	if target.Kind() == reflect.Ptr && target.IsNil() {
+		return nil, false
+	}
+
+	if target.Kind() != reflect.Ptr && target.CanAddr() {
+		target = target.Addr()
+	}
+
+	unmarshaler, ok := target.Interface().(FaunaUnmarshaler)
+	return unmarshaler, ok
+}
@@ -0,0 +1,58 @@
+package faunadb
+
+import "context"
+
+// Decode decodes a FaunaDB value to a native Go type, as Value.Get does.
+//
+// For example:
+//
+//	user, err := Decode[User](value)
+func Decode[T any](v Value) (T, error) {
+	var result T
+	err := v.Get(&result)
+	return result, err
+}
+
+// DecodeAt extracts the given field from a FaunaDB value and decodes it to a native Go type.
+// Decoding errors report their location from the root of the value, including the field path.
+//
+// For example:
+//
+//	name, err := DecodeAt[string](value, ObjKey("data", "name"))
+func DecodeAt[T any](v Value, field Field) (T, error) {
+	var result T
+
+	value, err := v.At(field).GetValue()
+	if err != nil {
+		return result, err
+	}
+
+	if err = value.Get(&result); err != nil {
+		if decodeErr, ok := err.(DecodeError); ok {
+			err = DecodeError{path: field.path, err: decodeErr}
+		}
+	}
+
+	return result, err
+}
+
+// QueryAs sends a query language expression to FaunaDB, as FaunaClient.Query does, and decodes its
+// result to a native Go type.
+//
+// For example:
+//
+//	user, err := QueryAs[User](client, Select("data", Get(Ref(Collection("users"), "1"))))
+func QueryAs[T any](client *FaunaClient, expr Expr, configs ...QueryConfig) (T, error) {
+	return QueryAsContext[T](context.Background(), client, expr, configs...)
+}
+
+// QueryAsContext is QueryAs using the provided context, as FaunaClient.QueryContext does.
+func QueryAsContext[T any](ctx context.Context, client *FaunaClient, expr Expr, configs ...QueryConfig) (T, error) {
+	value, err := client.QueryContext(ctx, expr, configs...)
+	if err != nil {
+		var result T
+		return result, err
+	}
+
+	return Decode[T](value)
+}
@@ -0,0 +1,59 @@
+package faunadb
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	value := ObjectV{"name": StringV("Jhon"), "age": LongV(42)}
+
+	user, err := Decode[struct {
+		Name string `fauna:"name"`
+		Age  int    `fauna:"age"`
+	}](value)
+	require.NoError(t, err)
+	require.Equal(t, "Jhon", user.Name)
+	require.Equal(t, 42, user.Age)
+
+	tags, err := Decode[[]string](ArrayV{StringV("a"), StringV("b")})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, tags)
+}
+
+func TestDecodeError(t *testing.T) {
+	_, err := Decode[[]int](ArrayV{LongV(1), StringV("two")})
+	require.EqualError(t, err,
+		"Error while decoding fauna value at: 1. Can not assign value of type \"faunadb.StringV\" to a value of type \"int\"")
+}
+
+func TestDecodeAt(t *testing.T) {
+	value := ObjectV{"data": ObjectV{"tags": ArrayV{StringV("a"), StringV("b")}}}
+
+	tag, err := DecodeAt[string](value, ObjKey("data", "tags").AtIndex(1))
+	require.NoError(t, err)
+	require.Equal(t, "b", tag)
+
+	_, err = DecodeAt[[]int](value, ObjKey("data", "tags"))
+	require.EqualError(t, err,
+		"Error while decoding fauna value at: data / tags / 0. Can not assign value of type \"faunadb.StringV\" to a value of type \"int\"")
+
+	_, err = DecodeAt[string](value, ObjKey("data", "name"))
+	require.EqualError(t, err, "Error while extracting path: data / name. Object key name not found")
+}
+
+func TestQueryAs(t *testing.T) {
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"resource": {"name": "Jhon"}}`))
+	})
+	defer closeServer()
+
+	user, err := QueryAs[map[string]string](client, Obj{"name": "Jhon"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"name": "Jhon"}, user)
+
+	_, err = QueryAs[int](client, Obj{"name": "Jhon"})
+	require.EqualError(t, err, "Error while decoding fauna value at: <root>. Can not decode map into a value of type \"int\"")
+}
@@ -353,10 +353,103 @@ func TestDeserializeStructWithEmbeddedStructs(t *testing.T) {
 
 	var data Data
 
-	require.NoError(t, decodeJSON(`{"Int":42,"Embedded":{"Str":"a string"}}`, &data))
+	require.NoError(t, decodeJSON(`{"Int":42,"Str":"a string"}`, &data))
 	require.Equal(t, Data{42, Embedded{"a string"}}, data)
 }
 
+func TestDeserializeStructWithEmbeddedPointers(t *testing.T) {
+	type Base struct {
+		ID string `fauna:"id"`
+	}
+
+	type Data struct {
+		*Base
+		Int int
+	}
+
+	var data Data
+
+	require.NoError(t, decodeJSON(`{"id":"1","Int":42}`, &data))
+	require.Equal(t, Data{&Base{"1"}, 42}, data)
+
+	data = Data{}
+	require.NoError(t, decodeJSON(`{"Int":42}`, &data))
+	require.Equal(t, Data{nil, 42}, data)
+}
+
+func TestDeserializeStructWithEmbeddedConflicts(t *testing.T) {
+	type A struct {
+		Name string
+		Same string
+	}
+
+	type B struct {
+		Name string `fauna:"Name"`
+		Same string
+	}
+
+	type Data struct {
+		A
+		B
+	}
+
+	var data Data
+
+	require.NoError(t, decodeJSON(`{"Name":"name","Same":"same"}`, &data))
+	require.Equal(t, Data{B: B{Name: "name"}}, data)
+}
+
+func TestDeserializeStructWithInlineFields(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `fauna:"created_by"`
+	}
+
+	type Data struct {
+		Name  string         `fauna:"name"`
+		Audit Audit          `fauna:",inline"`
+		Extra map[string]int `fauna:",inline"`
+	}
+
+	var data Data
+
+	require.NoError(t, decodeJSON(`{"name":"Jhon","created_by":"admin","age":42,"score":7}`, &data))
+	require.Equal(t, Data{"Jhon", Audit{"admin"}, map[string]int{"age": 42, "score": 7}}, data)
+
+	require.EqualError(t,
+		decodeJSON(`{"name":"Jhon","age":"old"}`, &data),
+		"Error while decoding fauna value at: age. Can not assign value of type \"faunadb.StringV\" to a value of type \"int\"",
+	)
+}
+
+func TestReportInvalidInlineFields(t *testing.T) {
+	type withTime struct {
+		At time.Time `fauna:",inline"`
+	}
+
+	type withRef struct {
+		Ref RefV `fauna:",inline"`
+	}
+
+	type withString struct {
+		Name string `fauna:",inline"`
+	}
+
+	require.EqualError(t,
+		decodeJSON(`{}`, &withTime{}),
+		"Error while decoding fauna value at: <root>. fauna: inline option can not be used on field At, time.Time is opaque: it is encoded as a single value",
+	)
+
+	require.EqualError(t,
+		decodeJSON(`{}`, &withRef{}),
+		"Error while decoding fauna value at: <root>. fauna: inline option can not be used on field Ref, faunadb.RefV is opaque: it is encoded as a single value",
+	)
+
+	require.EqualError(t,
+		decodeJSON(`{}`, &withString{}),
+		"Error while decoding fauna value at: <root>. fauna: inline option requires a struct or a map with string keys, but field Name is a string",
+	)
+}
+
 func TestIgnoresUnmapedNamesInStruct(t *testing.T) {
 	var object struct{ Name string }
 
@@ -508,6 +601,65 @@ func TestDeserializeStructWithOmitEmptyTags(t *testing.T) {
 	require.Equal(t, object{"John", 0}, obj)
 }
 
+func TestReportInvalidTag(t *testing.T) {
+	type invalidTag struct {
+		Name string `fauna:"name,bogus"`
+	}
+
+	var obj invalidTag
+
+	require.EqualError(t,
+		decodeJSON(`{"name": "Jhon"}`, &obj),
+		"Error while decoding fauna value at: <root>. fauna: struct tag has invalid option: \"bogus\" on field Name of faunadb.invalidTag",
+	)
+}
+
+func TestDeserializeUnmarshaler(t *testing.T) {
+	var p point
+	require.NoError(t, decodeJSON(`[1, 2]`, &p))
+	require.Equal(t, point{1, 2}, p)
+
+	var obj struct {
+		Point   point            `fauna:"point"`
+		Pointer *point           `fauna:"pointer"`
+		Missing *point           `fauna:"missing"`
+		Points  []point          `fauna:"points"`
+		ByName  map[string]point `fauna:"by_name"`
+	}
+
+	require.NoError(t, decodeJSON(`{
+		"point": [1, 2],
+		"pointer": [3, 4],
+		"missing": null,
+		"points": [[5, 6]],
+		"by_name": {"p": [7, 8]}
+	}`, &obj))
+	require.Equal(t, point{1, 2}, obj.Point)
+	require.Equal(t, &point{3, 4}, obj.Pointer)
+	require.Nil(t, obj.Missing)
+	require.Equal(t, []point{{5, 6}}, obj.Points)
+	require.Equal(t, map[string]point{"p": {7, 8}}, obj.ByName)
+
+	require.EqualError(t,
+		decodeJSON(`{"points": [[1, 2], [3]]}`, &obj),
+		"Error while decoding fauna value at: Points / 1. expected 2 coordinates but got 1",
+	)
+}
+
+func TestDeserializeNullUnmarshaler(t *testing.T) {
+	var null nullable
+	require.NoError(t, NullV{}.Get(&null))
+	require.True(t, bool(null))
+}
+
+type nullable bool
+
+func (n *nullable) UnmarshalFauna(value Value) error {
+	_, isNull := value.(NullV)
+	*n = nullable(isNull)
+	return nil
+}
+
 func decodeJSON(raw string, target interface{}) (err error) {
 	buffer := []byte(raw)
 
@@ -9,10 +9,11 @@ import (
 )
 
 var (
-	exprType = reflect.TypeOf((*Expr)(nil)).Elem()
-	objType  = reflect.TypeOf((*Obj)(nil)).Elem()
-	arrType  = reflect.TypeOf((*Arr)(nil)).Elem()
-	timeType = reflect.TypeOf((*time.Time)(nil)).Elem()
+	exprType  = reflect.TypeOf((*Expr)(nil)).Elem()
+	objType   = reflect.TypeOf((*Obj)(nil)).Elem()
+	arrType   = reflect.TypeOf((*Arr)(nil)).Elem()
+	timeType  = reflect.TypeOf((*time.Time)(nil)).Elem()
+	valueType = reflect.TypeOf((*Value)(nil)).Elem()
 
 	maxSupportedUint = uint64(math.MaxInt64)
 
@@ -20,11 +21,22 @@ var (
 	errMaxSupportedUintExceeded = invalidExpr{errors.New("Error while encoding number to json: Uint value exceeds maximum int64")}
 )
 
+// FaunaMarshaler is implemented by types that can encode themselves into a FaunaDB expression.
+// The Expr returned by MarshalFauna is encoded as any other expression, such as Obj, Arr or a function call.
+// Native Go values must be wrapped in their Value type, such as StringV or LongV, to be returned as an Expr.
+type FaunaMarshaler interface {
+	MarshalFauna() (Expr, error)
+}
+
 func wrap(i interface{}) Expr {
 	if i == nil {
 		return NullV{}
 	}
 
+	if expr, ok := wrapMarshaler(i); ok {
+		return expr
+	}
+
 	value, valueType := indirectValue(i)
 	kind := value.Kind()
 
@@ -71,8 +83,7 @@ func wrap(i interface{}) Expr {
 			return TimeV(value.Interface().(time.Time))
 		}
 
-		value, _ = indirectValue(structToMap(value))
-		return wrapMap(value)
+		return wrapStruct(value)
 
 	case reflect.Slice, reflect.Array:
 		return wrapArray(value)
@@ -92,6 +103,40 @@ func wrapMap(value reflect.Value) Expr {
 	return unescapedObj{"object": obj}
 }
 
+func wrapStruct(value reflect.Value) Expr {
+	fields, err := cachedTypeFields(value.Type())
+	if err != nil {
+		return invalidExpr{fmt.Errorf("Error while encoding struct: %s", err)}
+	}
+
+	obj := make(unescapedObj, len(fields.list))
+
+	for _, f := range fields.list {
+		field, ok := fieldByIndex(value, f.index, false)
+		if !ok || !field.CanInterface() {
+			continue
+		}
+
+		if f.omitempty && isEmptyValue(field) {
+			continue
+		}
+
+		obj[f.name] = wrap(field.Interface())
+	}
+
+	if fields.inline != nil {
+		if inline, ok := fieldByIndex(value, fields.inline.index, false); ok {
+			for _, key := range inline.MapKeys() {
+				if _, found := obj[key.String()]; !found {
+					obj[key.String()] = wrap(inline.MapIndex(key).Interface())
+				}
+			}
+		}
+	}
+
+	return unescapedObj{"object": obj}
+}
+
 func wrapArray(value reflect.Value) Expr {
 	arr := make(unescapedArr, value.Len())
 
@@ -101,3 +146,52 @@ func wrapArray(value reflect.Value) Expr {
 
 	return arr
 }
+
+// wrapMarshaler encodes values implementing FaunaMarshaler, either directly or through pointers and interfaces.
+func wrapMarshaler(i interface{}) (Expr, bool) {
+	value, ok := i.(reflect.Value)
+	if !ok {
+		value = reflect.ValueOf(i)
+	}
+
+	for value.IsValid() {
+		kind := value.Kind()
+
+		if (kind == reflect.Ptr || kind == reflect.Interface) && value.IsNil() {
+			return nil, false
+		}
+
+		if marshaler, ok := marshalerOf(value); ok {
+			expr, err := marshaler.MarshalFauna()
+			if err != nil {
+				return invalidExpr{fmt.Errorf("Error while encoding %s: %s", value.Type(), err)}, true
+			}
+			return wrap(expr), true
+		}
+
+		if kind != reflect.Ptr && kind != reflect.Interface {
+			break
+		}
+
+		value = value.Elem()
+	}
+
+	return nil, false
+}
+
+func marshalerOf(value reflect.Value) (FaunaMarshaler, bool) {
+	if !value.CanInterface() {
+		return nil, false
+	}
+
+	if marshaler, ok := value.Interface().(FaunaMarshaler); ok {
+		return marshaler, true
+	}
+
+	if value.Kind() != reflect.Ptr && value.CanAddr() {
+		marshaler, ok := value.Addr().Interface().(FaunaMarshaler)
+		return marshaler, ok
+	}
+
+	return nil, false
+}
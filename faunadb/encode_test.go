@@ -1,7 +1,10 @@
 package faunadb
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
 )
 
@@ -112,3 +115,85 @@ func TestWrapSliceAndArrays(t *testing.T) {
 		}
 	}
 }
+
+type point struct{ X, Y int }
+
+func (p point) MarshalFauna() (Expr, error) { return Arr{p.X, p.Y}, nil }
+
+func (p *point) UnmarshalFauna(value Value) error {
+	var coords []int
+	if err := value.Get(&coords); err != nil {
+		return err
+	}
+	if len(coords) != 2 {
+		return fmt.Errorf("expected 2 coordinates but got %d", len(coords))
+	}
+	p.X, p.Y = coords[0], coords[1]
+	return nil
+}
+
+type invalidPoint struct{}
+
+func (p invalidPoint) MarshalFauna() (Expr, error) { return nil, errors.New("invalid point") }
+
+func TestWrapMarshaler(t *testing.T) {
+	wrappedPoint := func(x, y int64) Expr { return unescapedArr{LongV(x), LongV(y)} }
+
+	tests := []struct {
+		name     string
+		in       interface{}
+		expected Expr
+	}{
+		{"value", point{1, 2}, wrappedPoint(1, 2)},
+		{"pointer", &point{1, 2}, wrappedPoint(1, 2)},
+		{"nil pointer", (*point)(nil), NullV{}},
+		{"slice", []point{{1, 2}}, unescapedArr{wrappedPoint(1, 2)}},
+		{"map", map[string]*point{"p": {1, 2}}, unescapedObj{"object": unescapedObj{"p": wrappedPoint(1, 2)}}},
+		{"struct field", struct{ P point }{point{1, 2}}, unescapedObj{"object": unescapedObj{"P": wrappedPoint(1, 2)}}},
+		{"error", invalidPoint{}, invalidExpr{errors.New("Error while encoding faunadb.invalidPoint: invalid point")}},
+	}
+
+	for _, test := range tests {
+		actual := wrap(test.in)
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("Marshaler test %s failed: wrap(%#v) == %#v, expected %#v", test.name, test.in, actual, test.expected)
+		}
+	}
+}
+
+func TestWrapStructWithInvalidTag(t *testing.T) {
+	type invalidTag struct {
+		Name string `fauna:"name,bogus"`
+	}
+
+	actual := wrap(invalidTag{"Jhon"})
+	expected := invalidExpr{errors.New(`Error while encoding struct: fauna: struct tag has invalid option: "bogus" on field Name of faunadb.invalidTag`)}
+	if actual.(invalidExpr).err.Error() != expected.err.Error() {
+		t.Errorf("Invalid tag test failed: wrap(%#v) == %#v, expected %#v", invalidTag{}, actual, expected)
+	}
+}
+
+func TestCachedTypeFields(t *testing.T) {
+	type cached struct {
+		Name string `fauna:"name"`
+		Age  int    `fauna:"age,omitempty"`
+	}
+
+	var wg sync.WaitGroup
+	results := make([]structFields, 8)
+
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cachedTypeFields(reflect.TypeOf(cached{}))
+		}(i)
+	}
+	wg.Wait()
+
+	for _, fields := range results {
+		if !reflect.DeepEqual(fields, results[0]) || len(fields.list) != 2 || &fields.list[0] != &results[0].list[0] {
+			t.Errorf("Cached fields test failed: %#v, expected %#v", fields, results[0])
+		}
+	}
+}
@@ -5,11 +5,54 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
 )
 
 var errorsField = ObjKey("errors")
 
+// ErrorCode is a well-known error code returned by FaunaDB. Error codes can be matched against any
+// error returned by the driver with errors.Is:
+//
+//	if errors.Is(err, faunadb.ErrInstanceNotUnique) {
+//		// handle duplicated document
+//	}
+type ErrorCode string
+
+func (code ErrorCode) Error() string { return string(code) }
+
+// Error codes returned by FaunaDB.
+//
+// See: https://docs.fauna.com/fauna/v4/api/fql/errors
+const (
+	ErrBadRequest               ErrorCode = "bad request"
+	ErrCallError                ErrorCode = "call error"
+	ErrContendedTransaction     ErrorCode = "contended transaction"
+	ErrDuplicateValue           ErrorCode = "duplicate value"
+	ErrFeatureNotAvailable      ErrorCode = "feature not available"
+	ErrInstanceAlreadyExists    ErrorCode = "instance already exists"
+	ErrInstanceNotFound         ErrorCode = "instance not found"
+	ErrInstanceNotUnique        ErrorCode = "instance not unique"
+	ErrInternalError            ErrorCode = "internal error"
+	ErrInvalidArgument          ErrorCode = "invalid argument"
+	ErrInvalidExpression        ErrorCode = "invalid expression"
+	ErrInvalidObjectInContainer ErrorCode = "invalid object in container"
+	ErrInvalidRef               ErrorCode = "invalid ref"
+	ErrInvalidToken             ErrorCode = "invalid token"
+	ErrInvalidURLParameter      ErrorCode = "invalid url parameter"
+	ErrInvalidWriteTime         ErrorCode = "invalid write time"
+	ErrMethodNotAllowed         ErrorCode = "method not allowed"
+	ErrMissingIdentity          ErrorCode = "missing identity"
+	ErrPermissionDenied         ErrorCode = "permission denied"
+	ErrSchemaNotFound           ErrorCode = "schema not found"
+	ErrStackOverflow            ErrorCode = "stack overflow"
+	ErrTransactionAborted       ErrorCode = "transaction aborted"
+	ErrUnauthorized             ErrorCode = "unauthorized"
+	ErrUnboundVariable          ErrorCode = "unbound variable"
+	ErrValidationFailed         ErrorCode = "validation failed"
+	ErrValueNotFound            ErrorCode = "value not found"
+)
+
 // A FaunaError wraps HTTP errors when sending queries to a FaunaDB cluster.
 type FaunaError interface {
 	error
@@ -17,6 +60,13 @@ type FaunaError interface {
 	Errors() []QueryError // Errors returned by the server
 }
 
+// IsRetryable reports whether the error is a transient error response from FaunaDB, such as a conflict,
+// throttling or unavailability, for which the same query may succeed if sent again.
+func IsRetryable(err error) bool {
+	var retryable interface{ Retryable() bool }
+	return errors.As(err, &retryable) && retryable.Retryable()
+}
+
 // A BadRequest wraps an HTTP 400 error response.
 type BadRequest struct{ FaunaError }
 
@@ -29,6 +79,12 @@ type PermissionDenied struct{ FaunaError }
 // A NotFound wraps an HTTP 404 error response.
 type NotFound struct{ FaunaError }
 
+// A Conflict wraps an HTTP 409 error response, usually caused by a contended transaction.
+type Conflict struct{ FaunaError }
+
+// A TooManyRequests wraps an HTTP 429 error response.
+type TooManyRequests struct{ FaunaError }
+
 // A InternalError wraps an HTTP 500 error response.
 type InternalError struct{ FaunaError }
 
@@ -38,6 +94,60 @@ type Unavailable struct{ FaunaError }
 // A UnknownError wraps any unknown http error response.
 type UnknownError struct{ FaunaError }
 
+// Unwrap returns the underlying error response.
+func (err BadRequest) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err Unauthorized) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err PermissionDenied) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err NotFound) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err Conflict) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err TooManyRequests) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err InternalError) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err Unavailable) Unwrap() error { return err.FaunaError }
+
+// Unwrap returns the underlying error response.
+func (err UnknownError) Unwrap() error { return err.FaunaError }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err BadRequest) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err Unauthorized) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err PermissionDenied) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err NotFound) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err Conflict) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err TooManyRequests) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err InternalError) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err Unavailable) Retryable() bool { return IsRetryable(err.FaunaError) }
+
+// Retryable reports whether the underlying error response is transient. See IsRetryable.
+func (err UnknownError) Retryable() bool { return IsRetryable(err.FaunaError) }
+
 // QueryError describes query errors returned by the server.
 type QueryError struct {
 	Position    []string            `fauna:"position"`
@@ -62,6 +172,38 @@ type errorResponse struct {
 func (err errorResponse) Status() int          { return err.status }
 func (err errorResponse) Errors() []QueryError { return err.errors }
 
+// Retryable reports whether the error is transient: conflicts, throttling and unavailability.
+func (err errorResponse) Retryable() bool {
+	switch err.status {
+	case 409, 429, 503:
+		return true
+	default:
+		return false
+	}
+}
+
+// Is reports whether any of the errors returned by the server, or their causes, matches the target ErrorCode.
+func (err errorResponse) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	if !ok {
+		return false
+	}
+
+	for _, queryError := range err.errors {
+		if queryError.Code == string(code) {
+			return true
+		}
+
+		for _, cause := range queryError.Cause {
+			if cause.Code == string(code) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (err errorResponse) Error() string {
 	return fmt.Sprintf("Response error %d. %s", err.status, err.queryErrors())
 }
@@ -98,6 +240,10 @@ func checkForResponseErrors(response *http.Response) error {
 		return PermissionDenied{err}
 	case 404:
 		return NotFound{err}
+	case 409:
+		return Conflict{err}
+	case 429:
+		return TooManyRequests{err}
 	case 500:
 		return InternalError{err}
 	case 503:
@@ -112,6 +258,8 @@ func parseErrorResponse(response *http.Response) FaunaError {
 
 	if response.Body != nil {
 		if value, err := parseJSON(response.Body); err == nil {
+			normalizePositions(value.At(errorsField))
+
 			if err := value.At(errorsField).Get(&errors); err == nil {
 				return errorResponse{true, response.StatusCode, errors}
 			}
@@ -121,6 +269,42 @@ func parseErrorResponse(response *http.Response) FaunaError {
 	return errorResponse{false, response.StatusCode, errors}
 }
 
+type streamError struct {
+	code    string
+	message string
+}
+
+func (err streamError) Error() string { return err.message }
+
+// Is reports whether the stream error code matches the target ErrorCode.
+func (err streamError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && err.code == string(code)
+}
+
+// normalizePositions converts array indexes found in error positions to strings
+// so they can be decoded into QueryError and ValidationFailure positions.
+func normalizePositions(field FieldValue) {
+	var errs ArrayV
+	if err := field.Get(&errs); err != nil {
+		return
+	}
+
+	for _, e := range errs {
+		if obj, ok := e.(ObjectV); ok {
+			if position, ok := obj["position"].(ArrayV); ok {
+				for i, segment := range position {
+					if index, ok := segment.(LongV); ok {
+						position[i] = StringV(strconv.FormatInt(int64(index), 10))
+					}
+				}
+			}
+
+			normalizePositions(obj.At(ObjKey("cause")))
+		}
+	}
+}
+
 func errorFromStreamError(obj ObjectV) (err error) {
 	var sb strings.Builder
 	sb.WriteString("stream_error:")
@@ -134,10 +318,18 @@ func errorFromStreamError(obj ObjectV) (err error) {
 			sb.WriteString(" ")
 			sb.WriteString(k)
 			sb.WriteString("=")
-			sb.WriteString(fmt.Sprintf("'%s'", obj[k]))
+			if str, ok := obj[k].(StringV); ok {
+				sb.WriteString(fmt.Sprintf("'%s'", string(str)))
+			} else {
+				sb.WriteString(fmt.Sprintf("'%s'", obj[k]))
+			}
 		}
 
 	}
-	err = errors.New(sb.String())
+	var code string
+	if v, ok := obj["code"]; ok {
+		_ = v.Get(&code)
+	}
+	err = streamError{code, sb.String()}
 	return
 }
@@ -2,6 +2,8 @@ package faunadb
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"testing"
@@ -34,6 +36,16 @@ func TestReturnNotFoundOn404(t *testing.T) {
 	require.Equal(t, NotFound{errorResponseWith(404, noErrors)}, err)
 }
 
+func TestReturnConflictOn409(t *testing.T) {
+	err := checkForResponseErrors(httpErrorResponseWith(409, emptyErrorBody))
+	require.Equal(t, Conflict{errorResponseWith(409, noErrors)}, err)
+}
+
+func TestReturnTooManyRequestsOn429(t *testing.T) {
+	err := checkForResponseErrors(httpErrorResponseWith(429, emptyErrorBody))
+	require.Equal(t, TooManyRequests{errorResponseWith(429, noErrors)}, err)
+}
+
 func TestReturnInternalErrorOn500(t *testing.T) {
 	err := checkForResponseErrors(httpErrorResponseWith(500, emptyErrorBody))
 	require.Equal(t, InternalError{errorResponseWith(500, noErrors)}, err)
@@ -49,6 +61,26 @@ func TestReturnUnknownErrorByDefault(t *testing.T) {
 	require.Equal(t, UnknownError{errorResponseWith(1001, noErrors)}, err)
 }
 
+func TestRetryableErrors(t *testing.T) {
+	retryable := map[int]bool{
+		400: false, 401: false, 403: false, 404: false, 409: true,
+		429: true, 500: false, 503: true, 1001: false,
+	}
+
+	for status, expected := range retryable {
+		err := checkForResponseErrors(httpErrorResponseWith(status, emptyErrorBody))
+		require.Equal(t, expected, IsRetryable(err), "status %d", status)
+	}
+
+	require.True(t, IsRetryable(fmt.Errorf("query failed: %w", Conflict{errorResponseWith(409, noErrors)})))
+	require.False(t, IsRetryable(errors.New("connection refused")))
+}
+
+func TestConcreteErrorsRetryable(t *testing.T) {
+	require.True(t, Conflict{errorResponseWith(409, noErrors)}.Retryable())
+	require.False(t, BadRequest{errorResponseWith(400, noErrors)}.Retryable())
+}
+
 func TestParseErrorResponse(t *testing.T) {
 	json := `
 	{
@@ -94,6 +126,64 @@ func TestParseErrorResponse(t *testing.T) {
 	require.EqualError(t, err, "Response error 401. Errors: [data/token](invalid token): Invalid token., details: [{[data token] invalid token invalid token}]")
 }
 
+func TestMatchErrorCodes(t *testing.T) {
+	json := `
+	{
+		"errors": [
+			{
+				"position": [ "create" ],
+				"code": "validation failed",
+				"description": "document data is not valid.",
+				"cause": [
+					{
+						"position": [ "data", "email" ],
+						"code": "duplicate value",
+						"description": "Value is not unique."
+					}
+				]
+			}
+		]
+	}
+	`
+
+	for _, status := range []int{400, 401, 403, 404, 409, 429, 500, 503, 1001} {
+		err := checkForResponseErrors(httpErrorResponseWith(status, json))
+
+		require.True(t, errors.Is(err, ErrValidationFailed))
+		require.True(t, errors.Is(err, ErrDuplicateValue))
+		require.False(t, errors.Is(err, ErrInstanceNotUnique))
+
+		wrapped := fmt.Errorf("creating user: %w", err)
+		require.True(t, errors.Is(wrapped, ErrValidationFailed))
+
+		var faunaErr FaunaError
+		require.True(t, errors.As(wrapped, &faunaErr))
+		require.Equal(t, status, faunaErr.Status())
+	}
+}
+
+func TestMatchErrorTypes(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", checkForResponseErrors(httpErrorResponseWith(409, emptyErrorBody)))
+
+	var conflict Conflict
+	require.True(t, errors.As(err, &conflict))
+
+	var badRequest BadRequest
+	require.False(t, errors.As(err, &badRequest))
+}
+
+func TestMatchStreamErrorCodes(t *testing.T) {
+	err := errorFromStreamError(ObjectV{
+		"code":        StringV("permission denied"),
+		"description": StringV("Authorization lost during stream evaluation."),
+	})
+
+	event := ErrorEvent{err: err}
+	require.True(t, errors.Is(event.Error(), ErrPermissionDenied))
+	require.False(t, errors.Is(event.Error(), ErrInstanceNotFound))
+	require.EqualError(t, event.Error(), `stream_error: code='permission denied' description='Authorization lost during stream evaluation.'`)
+}
+
 func TestUnparseableResponse(t *testing.T) {
 	json := "can't parse this as an error"
 	err := checkForResponseErrors(httpErrorResponseWith(503, json))
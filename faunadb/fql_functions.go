@@ -0,0 +1,499 @@
+package faunadb
+
+import "fmt"
+
+// fqlFunction builds the value of a function call found in query language source text. The result is
+// either an Expr, an OptionalParameter or a *LetBuilder.
+type fqlFunction func(args []Expr, options []OptionalParameter) (interface{}, error)
+
+var fqlFunctions = map[string]fqlFunction{
+	"Let":       fqlLet,
+	"Var":       fqlVar,
+	"Ref":       fqlRef,
+	"OnlyFirst": fqlNullaryOption(OnlyFirst),
+	"QueryV":    fqlQueryV,
+
+	"Abort":                 fqlUnary(Abort),
+	"Abs":                   fqlUnary(Abs),
+	"AccessProvider":        fqlUnary(AccessProvider),
+	"AccessProviders":       fqlNullary(AccessProviders),
+	"Acos":                  fqlUnary(Acos),
+	"Add":                   fqlVariadic(Add),
+	"After":                 fqlOption(After),
+	"All":                   fqlUnary(All),
+	"And":                   fqlVariadic(And),
+	"Any":                   fqlUnary(Any),
+	"Append":                fqlBinary(Append),
+	"Asin":                  fqlUnary(Asin),
+	"At":                    fqlBinary(At),
+	"Atan":                  fqlUnary(Atan),
+	"Before":                fqlOption(Before),
+	"BitAnd":                fqlVariadic(BitAnd),
+	"BitNot":                fqlUnary(BitNot),
+	"BitOr":                 fqlVariadic(BitOr),
+	"BitXor":                fqlVariadic(BitXor),
+	"Call":                  fqlUnaryVariadic(Call),
+	"Casefold":              fqlUnaryWithOptions(Casefold),
+	"Ceil":                  fqlUnary(Ceil),
+	"Class":                 fqlUnary(Class),
+	"Classes":               fqlNullary(Classes),
+	"Collection":            fqlUnary(Collection),
+	"Collections":           fqlNullary(Collections),
+	"Concat":                fqlUnaryWithOptions(Concat),
+	"ConflictResolver":      fqlOption(ConflictResolver),
+	"Contains":              fqlBinary(Contains),
+	"ContainsField":         fqlBinary(ContainsField),
+	"ContainsPath":          fqlBinary(ContainsPath),
+	"ContainsStr":           fqlBinary(ContainsStr),
+	"ContainsStrRegex":      fqlBinary(ContainsStrRegex),
+	"ContainsValue":         fqlBinary(ContainsValue),
+	"Cos":                   fqlUnary(Cos),
+	"Cosh":                  fqlUnary(Cosh),
+	"Count":                 fqlUnary(Count),
+	"Create":                fqlBinary(Create),
+	"CreateAccessProvider":  fqlUnary(CreateAccessProvider),
+	"CreateClass":           fqlUnary(CreateClass),
+	"CreateCollection":      fqlUnary(CreateCollection),
+	"CreateDatabase":        fqlUnary(CreateDatabase),
+	"CreateFunction":        fqlUnary(CreateFunction),
+	"CreateIndex":           fqlUnary(CreateIndex),
+	"CreateKey":             fqlUnary(CreateKey),
+	"CreateRole":            fqlUnary(CreateRole),
+	"Credentials":           fqlNullary(Credentials),
+	"CurrentIdentity":       fqlNullary(CurrentIdentity),
+	"CurrentToken":          fqlNullary(CurrentToken),
+	"Cursor":                fqlOption(Cursor),
+	"Database":              fqlUnary(Database),
+	"Databases":             fqlNullary(Databases),
+	"Date":                  fqlUnary(Date),
+	"DayOfMonth":            fqlUnary(DayOfMonth),
+	"DayOfWeek":             fqlUnary(DayOfWeek),
+	"DayOfYear":             fqlUnary(DayOfYear),
+	"Default":               fqlOption(Default),
+	"Degrees":               fqlUnary(Degrees),
+	"Delete":                fqlUnary(Delete),
+	"Difference":            fqlVariadic(Difference),
+	"Distinct":              fqlUnary(Distinct),
+	"Divide":                fqlVariadic(Divide),
+	"Do":                    fqlVariadic(Do),
+	"Documents":             fqlUnary(Documents),
+	"Drop":                  fqlBinary(Drop),
+	"EndsWith":              fqlBinary(EndsWith),
+	"Epoch":                 fqlBinary(Epoch),
+	"Equals":                fqlVariadic(Equals),
+	"Events":                fqlUnary(Events),
+	"EventsOpt":             fqlOption(EventsOpt),
+	"Exists":                fqlUnaryWithOptions(Exists),
+	"Exp":                   fqlUnary(Exp),
+	"Filter":                fqlBinary(Filter),
+	"FindStr":               fqlBinaryWithOptions(FindStr),
+	"FindStrRegex":          fqlBinaryWithOptions(FindStrRegex),
+	"Floor":                 fqlUnary(Floor),
+	"Foreach":               fqlBinary(Foreach),
+	"Format":                fqlUnaryVariadic(Format),
+	"Function":              fqlUnary(Function),
+	"Functions":             fqlNullary(Functions),
+	"GT":                    fqlVariadic(GT),
+	"GTE":                   fqlVariadic(GTE),
+	"Get":                   fqlUnaryWithOptions(Get),
+	"HasCurrentIdentity":    fqlNullary(HasCurrentIdentity),
+	"HasCurrentToken":       fqlNullary(HasCurrentToken),
+	"HasIdentity":           fqlNullary(HasIdentity),
+	"Hour":                  fqlUnary(Hour),
+	"Hypot":                 fqlBinary(Hypot),
+	"Identify":              fqlBinary(Identify),
+	"Identity":              fqlNullary(Identity),
+	"If":                    fqlTernary(If),
+	"Index":                 fqlUnary(Index),
+	"Indexes":               fqlNullary(Indexes),
+	"Insert":                fqlQuaternary(Insert),
+	"Intersection":          fqlVariadic(Intersection),
+	"IsArray":               fqlUnary(IsArray),
+	"IsBoolean":             fqlUnary(IsBoolean),
+	"IsBytes":               fqlUnary(IsBytes),
+	"IsCollection":          fqlUnary(IsCollection),
+	"IsCredentials":         fqlUnary(IsCredentials),
+	"IsDatabase":            fqlUnary(IsDatabase),
+	"IsDate":                fqlUnary(IsDate),
+	"IsDoc":                 fqlUnary(IsDoc),
+	"IsDouble":              fqlUnary(IsDouble),
+	"IsEmpty":               fqlUnary(IsEmpty),
+	"IsFunction":            fqlUnary(IsFunction),
+	"IsIndex":               fqlUnary(IsIndex),
+	"IsInteger":             fqlUnary(IsInteger),
+	"IsKey":                 fqlUnary(IsKey),
+	"IsLambda":              fqlUnary(IsLambda),
+	"IsNonEmpty":            fqlUnary(IsNonEmpty),
+	"IsNull":                fqlUnary(IsNull),
+	"IsNumber":              fqlUnary(IsNumber),
+	"IsObject":              fqlUnary(IsObject),
+	"IsRef":                 fqlUnary(IsRef),
+	"IsRole":                fqlUnary(IsRole),
+	"IsSet":                 fqlUnary(IsSet),
+	"IsString":              fqlUnary(IsString),
+	"IsTimestamp":           fqlUnary(IsTimestamp),
+	"IsToken":               fqlUnary(IsToken),
+	"Join":                  fqlBinary(Join),
+	"KeyFromSecret":         fqlUnary(KeyFromSecret),
+	"Keys":                  fqlNullary(Keys),
+	"LT":                    fqlVariadic(LT),
+	"LTE":                   fqlVariadic(LTE),
+	"LTrim":                 fqlUnary(LTrim),
+	"Lambda":                fqlBinary(Lambda),
+	"Length":                fqlUnary(Length),
+	"Ln":                    fqlUnary(Ln),
+	"Log":                   fqlUnary(Log),
+	"Login":                 fqlBinary(Login),
+	"Logout":                fqlUnary(Logout),
+	"LowerCase":             fqlUnary(LowerCase),
+	"Map":                   fqlBinary(Map),
+	"Match":                 fqlUnary(Match),
+	"MatchTerm":             fqlBinary(MatchTerm),
+	"Max":                   fqlVariadic(Max),
+	"Mean":                  fqlUnary(Mean),
+	"Merge":                 fqlBinaryWithOptions(Merge),
+	"Min":                   fqlVariadic(Min),
+	"Minute":                fqlUnary(Minute),
+	"Modulo":                fqlVariadic(Modulo),
+	"Month":                 fqlUnary(Month),
+	"MoveDatabase":          fqlBinary(MoveDatabase),
+	"Multiply":              fqlVariadic(Multiply),
+	"NewId":                 fqlNullary(NewId),
+	"NextID":                fqlNullary(NextID),
+	"Normalizer":            fqlOption(Normalizer),
+	"Not":                   fqlUnary(Not),
+	"Now":                   fqlNullary(Now),
+	"Null":                  fqlNullary(Null),
+	"NumResults":            fqlOption(NumResults),
+	"Number":                fqlOption(Number),
+	"Or":                    fqlVariadic(Or),
+	"Paginate":              fqlUnaryWithOptions(Paginate),
+	"Pow":                   fqlBinary(Pow),
+	"Precision":             fqlOption(Precision),
+	"Prepend":               fqlBinary(Prepend),
+	"Query":                 fqlUnary(Query),
+	"RTrim":                 fqlUnary(RTrim),
+	"Radians":               fqlUnary(Radians),
+	"Range":                 fqlTernary(Range),
+	"Reduce":                fqlTernary(Reduce),
+	"RefClass":              fqlBinary(RefClass),
+	"RefCollection":         fqlBinary(RefCollection),
+	"RegexEscape":           fqlUnary(RegexEscape),
+	"Remove":                fqlTernary(Remove),
+	"Repeat":                fqlUnaryWithOptions(Repeat),
+	"Replace":               fqlBinary(Replace),
+	"ReplaceStr":            fqlTernary(ReplaceStr),
+	"ReplaceStrRegex":       fqlTernaryWithOptions(ReplaceStrRegex),
+	"Reverse":               fqlUnary(Reverse),
+	"Role":                  fqlUnary(Role),
+	"Roles":                 fqlNullary(Roles),
+	"Round":                 fqlUnaryWithOptions(Round),
+	"ScopedAccessProvider":  fqlBinary(ScopedAccessProvider),
+	"ScopedAccessProviders": fqlUnary(ScopedAccessProviders),
+	"ScopedClass":           fqlBinary(ScopedClass),
+	"ScopedClasses":         fqlUnary(ScopedClasses),
+	"ScopedCollection":      fqlBinary(ScopedCollection),
+	"ScopedCollections":     fqlUnary(ScopedCollections),
+	"ScopedCredentials":     fqlUnary(ScopedCredentials),
+	"ScopedDatabase":        fqlBinary(ScopedDatabase),
+	"ScopedDatabases":       fqlUnary(ScopedDatabases),
+	"ScopedFunction":        fqlBinary(ScopedFunction),
+	"ScopedFunctions":       fqlUnary(ScopedFunctions),
+	"ScopedIndex":           fqlBinary(ScopedIndex),
+	"ScopedIndexes":         fqlUnary(ScopedIndexes),
+	"ScopedKeys":            fqlUnary(ScopedKeys),
+	"ScopedRole":            fqlBinary(ScopedRole),
+	"ScopedRoles":           fqlUnary(ScopedRoles),
+	"ScopedTokens":          fqlUnary(ScopedTokens),
+	"Second":                fqlUnary(Second),
+	"Select":                fqlBinaryWithOptions(Select),
+	"SelectAll":             fqlBinary(SelectAll),
+	"Separator":             fqlOption(Separator),
+	"Sign":                  fqlUnary(Sign),
+	"Sin":                   fqlUnary(Sin),
+	"Singleton":             fqlUnary(Singleton),
+	"Sinh":                  fqlUnary(Sinh),
+	"Size":                  fqlOption(Size),
+	"Sources":               fqlOption(Sources),
+	"Space":                 fqlUnary(Space),
+	"Sqrt":                  fqlUnary(Sqrt),
+	"Start":                 fqlOption(Start),
+	"StartsWith":            fqlBinary(StartsWith),
+	"StrLength":             fqlOption(StrLength),
+	"SubString":             fqlBinaryWithOptions(SubString),
+	"Subtract":              fqlVariadic(Subtract),
+	"Sum":                   fqlUnary(Sum),
+	"TS":                    fqlOption(TS),
+	"Take":                  fqlBinary(Take),
+	"Tan":                   fqlUnary(Tan),
+	"Tanh":                  fqlUnary(Tanh),
+	"Time":                  fqlUnary(Time),
+	"TimeAdd":               fqlTernary(TimeAdd),
+	"TimeDiff":              fqlTernary(TimeDiff),
+	"TimeSubtract":          fqlTernary(TimeSubtract),
+	"TitleCase":             fqlUnary(TitleCase),
+	"ToArray":               fqlUnary(ToArray),
+	"ToDate":                fqlUnary(ToDate),
+	"ToDouble":              fqlUnary(ToDouble),
+	"ToInteger":             fqlUnary(ToInteger),
+	"ToMicros":              fqlUnary(ToMicros),
+	"ToMillis":              fqlUnary(ToMillis),
+	"ToNumber":              fqlUnary(ToNumber),
+	"ToObject":              fqlUnary(ToObject),
+	"ToSeconds":             fqlUnary(ToSeconds),
+	"ToString":              fqlUnary(ToString),
+	"ToTime":                fqlUnary(ToTime),
+	"Tokens":                fqlNullary(Tokens),
+	"Trim":                  fqlUnary(Trim),
+	"Trunc":                 fqlUnaryWithOptions(Trunc),
+	"Union":                 fqlVariadic(Union),
+	"Update":                fqlBinary(Update),
+	"UpperCase":             fqlUnary(UpperCase),
+	"Year":                  fqlUnary(Year),
+}
+
+func callFQLFunction(name string, args []interface{}) (interface{}, error) {
+	fn, ok := fqlFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+
+	var exprs []Expr
+	var options []OptionalParameter
+
+	for _, arg := range args {
+		switch a := arg.(type) {
+		case Expr:
+			exprs = append(exprs, a)
+		case OptionalParameter:
+			options = append(options, a)
+		default:
+			return nil, fmt.Errorf("invalid argument to %s: Let() must be completed with In()", name)
+		}
+	}
+
+	value, err := fn(exprs, options)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s", name, err)
+	}
+
+	return value, nil
+}
+
+func callFQLMethod(receiver interface{}, name string, args []interface{}) (interface{}, error) {
+	let, ok := receiver.(*LetBuilder)
+	if !ok {
+		return nil, fmt.Errorf("unknown method %s", name)
+	}
+
+	switch name {
+	case "Bind":
+		if len(args) != 2 {
+			return nil, fmt.Errorf("Bind expects 2 arguments but got %d", len(args))
+		}
+
+		key, isKey := args[0].(StringV)
+		value, isExpr := args[1].(Expr)
+		if !isKey || !isExpr {
+			return nil, fmt.Errorf("Bind expects a string key and an expression")
+		}
+
+		return let.Bind(string(key), value), nil
+
+	case "In":
+		if len(args) != 1 {
+			return nil, fmt.Errorf("In expects 1 argument but got %d", len(args))
+		}
+
+		in, isExpr := args[0].(Expr)
+		if !isExpr {
+			return nil, fmt.Errorf("In expects an expression")
+		}
+
+		return let.In(in), nil
+	}
+
+	return nil, fmt.Errorf("unknown method %s", name)
+}
+
+func checkFQLArgs(args []Expr, options []OptionalParameter, min, max int, acceptOptions bool) error {
+	if len(options) > 0 && !acceptOptions {
+		return fmt.Errorf("does not accept optional parameters")
+	}
+
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		expected := fmt.Sprintf("%d", min)
+		switch {
+		case max < 0:
+			expected = fmt.Sprintf("at least %d", min)
+		case max != min:
+			expected = fmt.Sprintf("%d to %d", min, max)
+		}
+
+		return fmt.Errorf("expects %s arguments but got %d", expected, len(args))
+	}
+
+	return nil
+}
+
+func fqlNullary(fn func() Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 0, 0, false); err != nil {
+			return nil, err
+		}
+		return fn(), nil
+	}
+}
+
+func fqlUnary(fn func(interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 1, 1, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0]), nil
+	}
+}
+
+func fqlUnaryWithOptions(fn func(interface{}, ...OptionalParameter) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 1, 1, true); err != nil {
+			return nil, err
+		}
+		return fn(args[0], options...), nil
+	}
+}
+
+func fqlBinary(fn func(a, b interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 2, 2, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0], args[1]), nil
+	}
+}
+
+func fqlBinaryWithOptions(fn func(a, b interface{}, options ...OptionalParameter) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 2, 2, true); err != nil {
+			return nil, err
+		}
+		return fn(args[0], args[1], options...), nil
+	}
+}
+
+func fqlTernary(fn func(a, b, c interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 3, 3, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0], args[1], args[2]), nil
+	}
+}
+
+func fqlTernaryWithOptions(fn func(a, b, c interface{}, options ...OptionalParameter) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 3, 3, true); err != nil {
+			return nil, err
+		}
+		return fn(args[0], args[1], args[2], options...), nil
+	}
+}
+
+func fqlQuaternary(fn func(a, b, c, d interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 4, 4, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0], args[1], args[2], args[3]), nil
+	}
+}
+
+func fqlVariadic(fn func(...interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 0, -1, false); err != nil {
+			return nil, err
+		}
+		return fn(fqlInterfaces(args)...), nil
+	}
+}
+
+func fqlUnaryVariadic(fn func(interface{}, ...interface{}) Expr) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 1, -1, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0], fqlInterfaces(args[1:])...), nil
+	}
+}
+
+func fqlOption(fn func(interface{}) OptionalParameter) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 1, 1, false); err != nil {
+			return nil, err
+		}
+		return fn(args[0]), nil
+	}
+}
+
+func fqlNullaryOption(fn func() OptionalParameter) fqlFunction {
+	return func(args []Expr, options []OptionalParameter) (interface{}, error) {
+		if err := checkFQLArgs(args, options, 0, 0, false); err != nil {
+			return nil, err
+		}
+		return fn(), nil
+	}
+}
+
+func fqlLet(args []Expr, options []OptionalParameter) (interface{}, error) {
+	if err := checkFQLArgs(args, options, 0, 2, false); err != nil {
+		return nil, err
+	}
+
+	switch len(args) {
+	case 0:
+		return Let(), nil
+	case 2:
+		return letFn{Let: wrap(args[0]), In: wrap(args[1])}, nil
+	}
+
+	return nil, fmt.Errorf("expects 0 or 2 arguments but got %d", len(args))
+}
+
+func fqlVar(args []Expr, options []OptionalParameter) (interface{}, error) {
+	if err := checkFQLArgs(args, options, 1, 1, false); err != nil {
+		return nil, err
+	}
+
+	if name, ok := args[0].(StringV); ok {
+		return Var(string(name)), nil
+	}
+
+	return varFn{Var: args[0]}, nil
+}
+
+func fqlRef(args []Expr, options []OptionalParameter) (interface{}, error) {
+	if err := checkFQLArgs(args, options, 1, 2, false); err != nil {
+		return nil, err
+	}
+	return Ref(args[0], fqlInterfaces(args[1:])...), nil
+}
+
+func fqlQueryV(args []Expr, options []OptionalParameter) (interface{}, error) {
+	if err := checkFQLArgs(args, options, 1, 1, false); err != nil {
+		return nil, err
+	}
+
+	if lambda, ok := args[0].(StringV); ok {
+		return QueryV{[]byte(lambda)}, nil
+	}
+
+	return nil, fmt.Errorf("expects a string")
+}
+
+func fqlInterfaces(args []Expr) []interface{} {
+	res := make([]interface{}, len(args))
+	for i, arg := range args {
+		res[i] = arg
+	}
+	return res
+}
@@ -0,0 +1,460 @@
+package faunadb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FQLSyntaxError describes an error found while parsing query language source text.
+type FQLSyntaxError struct {
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (err FQLSyntaxError) Error() string {
+	return fmt.Sprintf("FQL syntax error at line %d, column %d: %s", err.Line, err.Column, err.Msg)
+}
+
+/*
+ParseFQL parses the query language source text into an expression. The source text uses the same syntax
+as the driver functions, and the same representation produced by ExprString. For example:
+
+	expr, err := ParseFQL(`Map(
+		Paginate(Documents(Collection("users")), Size(10)),
+		Lambda("ref", Get(Var("ref")))
+	)`)
+
+Objects and arrays are written as Obj{"key": value} and Arr{value}, and literals can be strings, numbers,
+booleans and null. Package qualifiers, such as f.Get, and line comments are ignored.
+*/
+func ParseFQL(src string) (Expr, error) {
+	p := &fqlParser{lexer: fqlLexer{src: src, line: 1, column: 1}}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.token.kind != fqlEOF {
+		return nil, p.errorf("unexpected %s after expression", p.token)
+	}
+
+	expr, ok := value.(Expr)
+	if !ok {
+		return nil, p.errorf("source text is not an expression")
+	}
+
+	return expr, nil
+}
+
+type fqlTokenKind int
+
+const (
+	fqlEOF fqlTokenKind = iota
+	fqlIdent
+	fqlString
+	fqlNumber
+	fqlPunct
+)
+
+type fqlToken struct {
+	kind   fqlTokenKind
+	text   string
+	line   int
+	column int
+}
+
+func (t fqlToken) String() string {
+	if t.kind == fqlEOF {
+		return "end of input"
+	}
+	return strconv.Quote(t.text)
+}
+
+type fqlLexer struct {
+	src    string
+	pos    int
+	line   int
+	column int
+}
+
+func (l *fqlLexer) peek() rune {
+	r, _ := utf8.DecodeRuneInString(l.src[l.pos:])
+	return r
+}
+
+func (l *fqlLexer) advance() rune {
+	r, size := utf8.DecodeRuneInString(l.src[l.pos:])
+	l.pos += size
+
+	if r == '\n' {
+		l.line++
+		l.column = 1
+	} else {
+		l.column++
+	}
+
+	return r
+}
+
+func (l *fqlLexer) skipSpaceAndComments() {
+	for l.pos < len(l.src) {
+		if r := l.peek(); unicode.IsSpace(r) {
+			l.advance()
+		} else if strings.HasPrefix(l.src[l.pos:], "//") {
+			for l.pos < len(l.src) && l.peek() != '\n' {
+				l.advance()
+			}
+		} else {
+			return
+		}
+	}
+}
+
+func (l *fqlLexer) next() (token fqlToken, err error) {
+	l.skipSpaceAndComments()
+
+	token = fqlToken{line: l.line, column: l.column}
+	start := l.pos
+
+	if l.pos >= len(l.src) {
+		return
+	}
+
+	r := l.peek()
+
+	switch {
+	case r == '_' || unicode.IsLetter(r):
+		token.kind = fqlIdent
+		for l.pos < len(l.src) && (l.peek() == '_' || unicode.IsLetter(l.peek()) || unicode.IsDigit(l.peek())) {
+			l.advance()
+		}
+
+	case r == '-' || unicode.IsDigit(r):
+		token.kind = fqlNumber
+		l.advance()
+		for l.pos < len(l.src) && strings.ContainsRune("0123456789.eE+-xXabcdefABCDEF", l.peek()) {
+			if prev := l.src[l.pos-1]; (l.peek() == '+' || l.peek() == '-') && prev != 'e' && prev != 'E' {
+				break
+			}
+			l.advance()
+		}
+
+	case r == '"' || r == '`':
+		token.kind = fqlString
+		quote := l.advance()
+		for {
+			if l.pos >= len(l.src) {
+				err = FQLSyntaxError{token.line, token.column, "unterminated string literal"}
+				return
+			}
+			c := l.advance()
+			if c == '\\' && quote == '"' && l.pos < len(l.src) {
+				l.advance()
+			} else if c == quote {
+				break
+			}
+		}
+
+	case strings.ContainsRune("(){},:.", r):
+		token.kind = fqlPunct
+		l.advance()
+
+	default:
+		err = FQLSyntaxError{token.line, token.column, fmt.Sprintf("unexpected character %q", r)}
+		return
+	}
+
+	token.text = l.src[start:l.pos]
+	return
+}
+
+type fqlParser struct {
+	lexer fqlLexer
+	token fqlToken
+}
+
+func (p *fqlParser) next() (err error) {
+	p.token, err = p.lexer.next()
+	return
+}
+
+func (p *fqlParser) errorf(format string, args ...interface{}) error {
+	return FQLSyntaxError{p.token.line, p.token.column, fmt.Sprintf(format, args...)}
+}
+
+func (p *fqlParser) isPunct(punct string) bool {
+	return p.token.kind == fqlPunct && p.token.text == punct
+}
+
+func (p *fqlParser) expect(punct string) error {
+	if !p.isPunct(punct) {
+		return p.errorf("expected %q but got %s", punct, p.token)
+	}
+	return p.next()
+}
+
+// parseValue parses an expression, an optional parameter or a let builder.
+func (p *fqlParser) parseValue() (interface{}, error) {
+	token := p.token
+
+	switch token.kind {
+	case fqlString:
+		str, err := strconv.Unquote(token.text)
+		if err != nil {
+			return nil, p.errorf("invalid string literal %s", token.text)
+		}
+		return StringV(str), p.next()
+
+	case fqlNumber:
+		num, err := parseFQLNumber(token.text)
+		if err != nil {
+			return nil, p.errorf("invalid number literal %s", token.text)
+		}
+		return num, p.next()
+
+	case fqlIdent:
+		return p.parseIdent()
+
+	case fqlEOF:
+		return nil, p.errorf("unexpected end of input")
+
+	default:
+		return nil, p.errorf("unexpected %s", token)
+	}
+}
+
+func (p *fqlParser) parseIdent() (interface{}, error) {
+	token := p.token
+	name := token.text
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	// Package qualifier, such as f.Get
+	if p.isPunct(".") {
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.token.kind != fqlIdent {
+			return nil, p.errorf("expected identifier after %q but got %s", name+".", p.token)
+		}
+		return p.parseIdent()
+	}
+
+	switch name {
+	case "true", "false":
+		return BooleanV(name == "true"), nil
+	case "null", "nil":
+		return NullV{}, nil
+	case "Obj", "ObjectV":
+		return p.parseObject()
+	case "Arr", "ArrayV":
+		return p.parseArray()
+	case "BytesV":
+		return p.parseBytes()
+	}
+
+	if !p.isPunct("(") {
+		return nil, FQLSyntaxError{token.line, token.column, fmt.Sprintf("unknown identifier %q", name)}
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := callFQLFunction(name, args)
+	if err != nil {
+		return nil, FQLSyntaxError{token.line, token.column, err.Error()}
+	}
+
+	for p.isPunct(".") {
+		if value, err = p.parseMethod(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return value, nil
+}
+
+func (p *fqlParser) parseMethod(receiver interface{}) (interface{}, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	token := p.token
+	if token.kind != fqlIdent {
+		return nil, p.errorf("expected method name but got %s", token)
+	}
+
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := callFQLMethod(receiver, token.text, args)
+	if err != nil {
+		return nil, FQLSyntaxError{token.line, token.column, err.Error()}
+	}
+
+	return value, nil
+}
+
+func (p *fqlParser) parseArgs() (args []interface{}, err error) {
+	if err = p.expect("("); err != nil {
+		return
+	}
+
+	for !p.isPunct(")") {
+		var arg interface{}
+		if arg, err = p.parseValue(); err != nil {
+			return
+		}
+		args = append(args, arg)
+
+		if !p.isPunct(",") {
+			break
+		}
+		if err = p.next(); err != nil {
+			return
+		}
+	}
+
+	err = p.expect(")")
+	return
+}
+
+func (p *fqlParser) parseObject() (Expr, error) {
+	obj := Obj{}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for !p.isPunct("}") {
+		if p.token.kind != fqlString {
+			return nil, p.errorf("expected object key but got %s", p.token)
+		}
+
+		key, err := strconv.Unquote(p.token.text)
+		if err != nil {
+			return nil, p.errorf("invalid string literal %s", p.token.text)
+		}
+
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		if err = p.expect(":"); err != nil {
+			return nil, err
+		}
+
+		if obj[key], err = p.parseExpr(); err != nil {
+			return nil, err
+		}
+
+		if !p.isPunct(",") {
+			break
+		}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return obj, p.expect("}")
+}
+
+func (p *fqlParser) parseArray() (Expr, error) {
+	arr := Arr{}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for !p.isPunct("}") {
+		elem, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, elem)
+
+		if !p.isPunct(",") {
+			break
+		}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return arr, p.expect("}")
+}
+
+func (p *fqlParser) parseBytes() (Expr, error) {
+	bytes := BytesV{}
+
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	for !p.isPunct("}") {
+		if p.token.kind != fqlNumber {
+			return nil, p.errorf("expected byte but got %s", p.token)
+		}
+
+		b, err := strconv.ParseUint(p.token.text, 0, 8)
+		if err != nil {
+			return nil, p.errorf("invalid byte %s", p.token.text)
+		}
+		bytes = append(bytes, byte(b))
+
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+		if !p.isPunct(",") {
+			break
+		}
+		if err = p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	return bytes, p.expect("}")
+}
+
+func (p *fqlParser) parseExpr() (Expr, error) {
+	token := p.token
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	if expr, ok := value.(Expr); ok {
+		return expr, nil
+	}
+
+	return nil, FQLSyntaxError{token.line, token.column, fmt.Sprintf("%s is not an expression", token)}
+}
+
+func parseFQLNumber(text string) (Expr, error) {
+	if strings.ContainsAny(text, ".eE") && !strings.HasPrefix(strings.TrimPrefix(text, "-"), "0x") {
+		num, err := strconv.ParseFloat(text, 64)
+		return DoubleV(num), err
+	}
+
+	num, err := strconv.ParseInt(text, 0, 64)
+	return LongV(num), err
+}
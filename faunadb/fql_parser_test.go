@@ -0,0 +1,95 @@
+package faunadb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFQLRoundTrip(t *testing.T) {
+	for _, c := range reprCases {
+		expr, err := ParseFQL(c.fql)
+		require.NoError(t, err, c.fql)
+		require.Equal(t, c.fql, ExprString(expr))
+
+		expected, _ := json.Marshal(c.expr)
+		actual, _ := json.Marshal(expr)
+		require.JSONEq(t, string(expected), string(actual), c.fql)
+	}
+}
+
+func TestParseFQLLiterals(t *testing.T) {
+	cases := map[string]Expr{
+		`"str"`:                StringV("str"),
+		"`raw\\str`":           StringV(`raw\str`),
+		`42`:                   LongV(42),
+		`-7`:                   LongV(-7),
+		`0x10`:                 LongV(16),
+		`3.14`:                 DoubleV(3.14),
+		`-1e3`:                 DoubleV(-1000),
+		`true`:                 BooleanV(true),
+		`false`:                BooleanV(false),
+		`null`:                 NullV{},
+		`Null()`:               NullV{},
+		`Obj{"a": 1, "b": 2,}`: Obj{"a": LongV(1), "b": LongV(2)},
+		`Arr{1, "a"}`:          Arr{LongV(1), StringV("a")},
+		`BytesV{0x01, 255}`:    BytesV{1, 255},
+	}
+
+	for src, expected := range cases {
+		expr, err := ParseFQL(src)
+		require.NoError(t, err, src)
+		require.Equal(t, expected, expr, src)
+	}
+}
+
+func TestParseFQLDriverSyntax(t *testing.T) {
+	expr, err := ParseFQL(`
+		// Fetch every user
+		f.Map(
+			f.Paginate(f.Documents(f.Collection("users")), f.Size(10)),
+			f.Lambda("ref", f.Get(f.Var("ref"))),
+		)`)
+
+	require.NoError(t, err)
+	require.Equal(t, `Map(Paginate(Documents(Collection("users")), Size(10)), Lambda("ref", Get(Var("ref"))))`, ExprString(expr))
+}
+
+func TestParseFQLLet(t *testing.T) {
+	expr, err := ParseFQL(`Let().Bind("x", 1).Bind("y", Var("x")).In(Var("y"))`)
+
+	require.NoError(t, err)
+	require.Equal(t, Let().Bind("x", LongV(1)).Bind("y", Var("x")).In(Var("y")), expr)
+}
+
+func TestParseFQLErrors(t *testing.T) {
+	cases := []struct {
+		src    string
+		line   int
+		column int
+		msg    string
+	}{
+		{``, 1, 1, "unexpected end of input"},
+		{`Get(Ref(Collection("users"), "1")`, 1, 34, `expected ")" but got end of input`},
+		{"Add(1,\n  2 3)", 2, 5, `expected ")" but got "3"`},
+		{`Foo(1)`, 1, 1, "unknown function Foo"},
+		{"Do(\n\tAdd(1), users)", 2, 10, `unknown identifier "users"`},
+		{`Lambda("x")`, 1, 1, "Lambda expects 2 arguments but got 1"},
+		{`Add(1, Size(2))`, 1, 1, "Add does not accept optional parameters"},
+		{`"unterminated`, 1, 1, "unterminated string literal"},
+		{`Add(1, #)`, 1, 8, `unexpected character '#'`},
+		{`Obj{a: 1}`, 1, 5, `expected object key but got "a"`},
+		{`Size(10)`, 1, 9, "source text is not an expression"},
+		{`Let().Bind("x", 1)`, 1, 19, "source text is not an expression"},
+		{`1 2`, 1, 3, `unexpected "2" after expression`},
+	}
+
+	for _, c := range cases {
+		_, err := ParseFQL(c.src)
+		require.Equal(t, FQLSyntaxError{c.line, c.column, c.msg}, err, c.src)
+	}
+
+	_, err := ParseFQL("Add(1,\n  2 3)")
+	require.EqualError(t, err, `FQL syntax error at line 2, column 5: expected ")" but got "3"`)
+}
@@ -176,7 +176,7 @@ func Call(ref interface{}, args ...interface{}) Expr {
 type callFn struct {
 	fnApply
 	Call   Expr `json:"call"`
-	Params Expr `json:"arguments"`
+	Params Expr `json:"arguments" faunarepr:"varargs"`
 }
 
 // Query creates an instance of the @query type with the specified lambda
@@ -617,7 +617,7 @@ func CurrentIdentity() Expr {
 
 type currentIdentityFn struct {
 	fnApply
-	CurrentIdentity Expr `json:"current_identity"`
+	CurrentIdentity Expr `json:"current_identity" faunarepr:"noargs"`
 }
 
 func CurrentToken() Expr {
@@ -628,7 +628,7 @@ func CurrentToken() Expr {
 
 type currentTokenFn struct {
 	fnApply
-	CurrentToken Expr `json:"current_token"`
+	CurrentToken Expr `json:"current_token" faunarepr:"noargs"`
 }
 
 func HasCurrentIdentity() Expr {
@@ -639,7 +639,7 @@ func HasCurrentIdentity() Expr {
 
 type hasCurrentIdentityFn struct {
 	fnApply
-	HasCurrentIdentity Expr `json:"has_current_identity"`
+	HasCurrentIdentity Expr `json:"has_current_identity" faunarepr:"noargs"`
 }
 
 func HasCurrentToken() Expr {
@@ -650,5 +650,5 @@ func HasCurrentToken() Expr {
 
 type hasCurrentTokenFn struct {
 	fnApply
-	HasCurrentToken Expr `json:"has_current_token"`
+	HasCurrentToken Expr `json:"has_current_token" faunarepr:"noargs"`
 }
\ No newline at end of file
@@ -21,7 +21,7 @@ func Format(format interface{}, values ...interface{}) Expr {
 type formatFn struct {
 	fnApply
 	Format Expr `json:"format"`
-	Values Expr `json:"values"`
+	Values Expr `json:"values" faunarepr:"varargs"`
 }
 
 // Concat concatenates a list of strings into a single string.
@@ -0,0 +1,289 @@
+package faunadb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// InvalidExprJSON describes an error that occurs when a JSON document can not be decoded into an expression.
+type InvalidExprJSON struct {
+	keys []string
+}
+
+func (i InvalidExprJSON) Error() string {
+	return fmt.Sprintf("Error while decoding expression: no function matches the keys [%s]", strings.Join(i.keys, ", "))
+}
+
+/*
+UnmarshalExpr decodes the wire format of an expression, as produced by json.Marshal, back into an expression.
+Function calls are rebuilt into the same structures returned by the query language functions, and escaped values
+are decoded as their Value types. For example:
+
+	buffer, _ := json.Marshal(f.Get(f.Ref(f.Collection("users"), "1")))
+
+	expr, err := f.UnmarshalExpr(buffer) // Returns f.Get(f.Ref(f.Collection("users"), "1"))
+*/
+func UnmarshalExpr(buffer []byte) (Expr, error) {
+	return decodeExpr(json.RawMessage(buffer))
+}
+
+func decodeExpr(raw json.RawMessage) (Expr, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("Error while decoding expression: empty input")
+	}
+
+	switch trimmed[0] {
+	case '[':
+		return decodeExprArray(trimmed)
+	case '{':
+		return decodeExprObject(trimmed)
+	default:
+		return parseJSON(bytes.NewReader(trimmed))
+	}
+}
+
+func decodeExprArray(raw json.RawMessage) (Expr, error) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, err
+	}
+
+	arr := make(unescapedArr, len(elems))
+
+	for i, elem := range elems {
+		expr, err := decodeExpr(elem)
+		if err != nil {
+			return nil, err
+		}
+		arr[i] = expr
+	}
+
+	return arr, nil
+}
+
+func decodeExprObject(raw json.RawMessage) (Expr, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 1 {
+		for key, value := range fields {
+			if isValueEscape(key, value) {
+				return parseJSON(bytes.NewReader(raw))
+			}
+
+			if key == "object" {
+				return decodeEscapedObject(value)
+			}
+		}
+	}
+
+	if _, ok := fields["let"]; ok && len(fields) == 2 {
+		if _, ok := fields["in"]; ok {
+			return decodeLet(fields["let"], fields["in"])
+		}
+	}
+
+	exprs := make(map[string]Expr, len(fields))
+
+	for key, value := range fields {
+		expr, err := decodeExpr(value)
+		if err != nil {
+			return nil, err
+		}
+		exprs[key] = expr
+	}
+
+	return decodeFn(exprs)
+}
+
+// decodeLet decodes the let bindings, which are written as unescaped objects.
+func decodeLet(rawBindings, rawIn json.RawMessage) (Expr, error) {
+	in, err := decodeExpr(rawIn)
+	if err != nil {
+		return nil, err
+	}
+
+	var bindings []json.RawMessage
+	if err := json.Unmarshal(rawBindings, &bindings); err != nil {
+		binding, err := decodeBinding(rawBindings)
+		if err != nil {
+			return nil, err
+		}
+
+		return letFn{Let: binding, In: in}, nil
+	}
+
+	let := make(unescapedArr, len(bindings))
+
+	for i, rawBinding := range bindings {
+		if let[i], err = decodeBinding(rawBinding); err != nil {
+			return nil, err
+		}
+	}
+
+	return letFn{Let: let, In: in}, nil
+}
+
+func decodeBinding(raw json.RawMessage) (Expr, error) {
+	escaped, err := decodeEscapedObject(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return escaped.(unescapedObj)["object"], nil
+}
+
+func isValueEscape(key string, value json.RawMessage) bool {
+	switch key {
+	case "@set", "@ts", "@date", "@bytes", "@query", "@obj":
+		return true
+	case "@ref":
+		// Ref("collections/users") is written as {"@ref": "collections/users"}
+		return bytes.HasPrefix(bytes.TrimSpace(value), []byte("{"))
+	}
+
+	return false
+}
+
+func decodeEscapedObject(raw json.RawMessage) (Expr, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	obj := make(unescapedObj, len(fields))
+
+	for key, value := range fields {
+		expr, err := decodeExpr(value)
+		if err != nil {
+			return nil, err
+		}
+		obj[key] = expr
+	}
+
+	return unescapedObj{"object": obj}, nil
+}
+
+func decodeFn(exprs map[string]Expr) (Expr, error) {
+	fnType, ok := fnTypeFor(exprs)
+	if !ok {
+		keys := make([]string, 0, len(exprs))
+		for key := range exprs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		return nil, InvalidExprJSON{keys}
+	}
+
+	fn := reflect.New(fnType.typ).Elem()
+
+	for _, field := range fnType.fields {
+		if expr, ok := exprs[field.name]; ok {
+			fn.Field(field.index).Set(reflect.ValueOf(&expr).Elem())
+		}
+	}
+
+	return fn.Interface().(Expr), nil
+}
+
+type fnJSONField struct {
+	name      string
+	index     int
+	omitempty bool
+}
+
+type fnJSONType struct {
+	typ    reflect.Type
+	fields []fnJSONField
+}
+
+// matches returns true if the keys contain all required fields of the function, and no unknown fields.
+func (fn fnJSONType) matches(exprs map[string]Expr) bool {
+	known := 0
+
+	for _, field := range fn.fields {
+		if _, ok := exprs[field.name]; ok {
+			known++
+		} else if !field.omitempty {
+			return false
+		}
+	}
+
+	return known == len(exprs)
+}
+
+// fnTypeFor returns the function type matching the keys. Every function has a distinct set of keys.
+func fnTypeFor(exprs map[string]Expr) (fnJSONType, bool) {
+	for _, fn := range fnJSONTypes {
+		if fn.matches(exprs) {
+			return fn, true
+		}
+	}
+
+	return fnJSONType{}, false
+}
+
+var fnJSONTypes = func() (res []fnJSONType) {
+	for _, fn := range fnTypes {
+		typ := reflect.TypeOf(fn)
+		jsonType := fnJSONType{typ: typ}
+
+		for i, size := 0, typ.NumField(); i < size; i++ {
+			field := typ.Field(i)
+			if field.Anonymous {
+				continue
+			}
+
+			tag := strings.Split(field.Tag.Get("json"), ",")
+			jsonType.fields = append(jsonType.fields, fnJSONField{
+				name:      tag[0],
+				index:     i,
+				omitempty: len(tag) > 1 && tag[1] == "omitempty",
+			})
+		}
+
+		res = append(res, jsonType)
+	}
+
+	return
+}()
+
+var fnTypes = []Expr{
+	abortFn{}, absFn{}, accessProviderFn{}, accessProvidersFn{}, acosFn{}, addFn{}, allFn{}, andFn{},
+	anyFn{}, appendFn{}, asinFn{}, atFn{}, atanFn{}, bitAndFn{}, bitNotFn{}, bitOrFn{}, bitXorFn{},
+	callFn{}, casefoldFn{}, ceilFn{}, classFn{}, classesFn{}, collectionFn{}, collectionsFn{},
+	concatFn{}, containsFieldFn{}, containsFn{}, containsPathFn{}, containsStrFn{},
+	containsStrRegexFn{}, containsValueFn{}, cosFn{}, coshFn{}, countFn{}, createAccessProviderFn{},
+	createClassFn{}, createCollectionFn{}, createDatabaseFn{}, createFn{}, createFunctionFn{},
+	createIndexFn{}, createKeyFn{}, createRoleFn{}, credentialsFn{}, currentIdentityFn{},
+	currentTokenFn{}, databaseFn{}, databasesFn{}, dateFn{}, dayOfMonthFn{}, dayOfWeekFn{},
+	dayOfYearFn{}, degreesFn{}, deleteFn{}, differenceFn{}, distinctFn{}, divideFn{}, doFn{},
+	documentsFn{}, dropFn{}, endsWithFn{}, epochFn{}, equalsFn{}, eventsFn{}, existsFn{}, expFn{},
+	filterFn{}, findStrFn{}, findStrRegexFn{}, floorFn{}, foreachFn{}, formatFn{}, functionFn{},
+	functionsFn{}, getFn{}, gtFn{}, gteFn{}, hasCurrentIdentityFn{}, hasCurrentTokenFn{},
+	hasIdentityFn{}, hourFn{}, hypotFn{}, identifyFn{}, identityFn{}, ifFn{}, indexFn{}, indexesFn{},
+	insertFn{}, intersectionFn{}, isArrayFn{}, isBooleanFn{}, isBytesFn{}, isCollectionFn{},
+	isCredentialsFn{}, isDatabaseFn{}, isDateFn{}, isDocFn{}, isDoubleFn{}, isEmptyFn{},
+	isFunctionFn{}, isIndexFn{}, isIntegerFn{}, isKeyFn{}, isLambdaFn{}, isNonEmptyFn{}, isNullFn{},
+	isNumberFn{}, isObjectFn{}, isRefFn{}, isRoleFn{}, isSetFn{}, isStringFn{}, isTimestampFn{},
+	isTokenFn{}, joinFn{}, keyFromSecretFn{}, keysFn{}, lTrimFn{}, lambdaFn{}, legacyRefFn{},
+	lengthFn{}, letFn{}, lnFn{}, logFn{}, loginFn{}, logoutFn{}, lowercaseFn{}, ltFn{}, lteFn{},
+	mapFn{}, matchFn{}, maxFn{}, meanFn{}, mergeFn{}, minFn{}, minuteFn{}, moduloFn{}, monthFn{},
+	moveDatabaseFn{}, multiplyFn{}, newIDFn{}, nextIDFn{}, notFn{}, nowFn{}, orFn{}, paginateFn{},
+	powFn{}, prependFn{}, queryFn{}, rTrimFn{}, radiansFn{}, rangeFn{}, reduceFn{}, refFn{},
+	regexEscapeFn{}, removeFn{}, repeatFn{}, replaceFn{}, replaceStrFn{}, replaceStrRegexFn{},
+	reverseFn{}, roleFn{}, rolesFn{}, roundFn{}, secondFn{}, selectAllFn{}, selectFn{}, signFn{},
+	sinFn{}, singletonFn{}, sinhFn{}, spaceFn{}, sqrtFn{}, startsWithFn{}, subStringFn{}, subtractFn{},
+	sumFn{}, takeFn{}, tanFn{}, tanhFn{}, timeAddFn{}, timeDiffFn{}, timeFn{}, timeSubtractFn{},
+	titleCaseFn{}, toArrayFn{}, toDateFn{}, toDoubleFn{}, toIntegerFn{}, toMicrosFn{}, toMillisFn{},
+	toNumberFn{}, toObjectFn{}, toSecondsFn{}, toStringFn{}, toTimeFn{}, tokensFn{}, trimFn{},
+	truncFn{}, unionFn{}, updateFn{}, upperCaseFn{}, varFn{}, yearFn{},
+}
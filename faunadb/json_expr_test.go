@@ -0,0 +1,82 @@
+package faunadb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalExprRoundTrip(t *testing.T) {
+	for _, c := range reprCases {
+		buffer, err := json.Marshal(c.expr)
+		require.NoError(t, err)
+
+		expr, err := UnmarshalExpr(buffer)
+		require.NoError(t, err, string(buffer))
+
+		// Whole doubles are written without a fraction, thus they decode as longs
+		if _, isDouble := c.expr.(DoubleV); !isDouble {
+			require.Equal(t, c.fql, ExprString(expr))
+		}
+
+		actual, err := json.Marshal(expr)
+		require.NoError(t, err)
+		require.JSONEq(t, string(buffer), string(actual))
+	}
+}
+
+func TestUnmarshalExprRebuildsFunctions(t *testing.T) {
+	queries := []Expr{
+		Get(Ref(Collection("users"), "1")),
+		Paginate(Documents(Collection("users")), Size(10), After(Arr{1, "a"})),
+		Let().Bind("x", 1).Bind("y", Obj{"a": Var("x")}).In(Add(Var("x"), 2)),
+		Map(Arr{1, 2}, Lambda("x", Multiply(Var("x"), 2))),
+		Create(Collection("users"), Obj{"data": Obj{"name": "John", "tags": Arr{"a", "b"}}}),
+		Call(Function("fn"), 1, 2),
+		Ref("collections/users"),
+		Select(Arr{"data", "name"}, Get(Var("ref")), Default(Null())),
+	}
+
+	for _, query := range queries {
+		buffer, err := json.Marshal(query)
+		require.NoError(t, err)
+
+		expr, err := UnmarshalExpr(buffer)
+		require.NoError(t, err)
+		require.Equal(t, query, expr)
+	}
+}
+
+func TestUnmarshalExprValues(t *testing.T) {
+	expr, err := UnmarshalExpr([]byte(`{
+		"update": {"@ref": {"id": "1", "collection": {"@ref": {"id": "users", "collection": {"@ref": {"id": "collections"}}}}}},
+		"params": {"object": {"ts": {"@ts": "2020-01-02T03:04:05Z"}, "bytes": {"@bytes": "AQI="}, "obj": {"@obj": {"n": 1.5}}}}
+	}`))
+	require.NoError(t, err)
+
+	require.Equal(t,
+		`Update(RefCollection(Collection("users"), "1"), Obj{"bytes": BytesV{0x01, 0x02}, "obj": Obj{"n": 1.5}, "ts": Time("2020-01-02T03:04:05Z")})`,
+		ExprString(expr),
+	)
+}
+
+func TestUnmarshalExprLegacyLet(t *testing.T) {
+	expr, err := UnmarshalExpr([]byte(`{"let": {"x": 1}, "in": {"var": "x"}}`))
+	require.NoError(t, err)
+	require.Equal(t, letFn{Let: unescapedObj{"x": LongV(1)}, In: Var("x")}, expr)
+}
+
+func TestUnmarshalExprErrors(t *testing.T) {
+	_, err := UnmarshalExpr([]byte(`{"get": 1, "unknown": 2}`))
+	require.EqualError(t, err, "Error while decoding expression: no function matches the keys [get, unknown]")
+
+	_, err = UnmarshalExpr([]byte(`{"add": [1, {"nope": true}]}`))
+	require.EqualError(t, err, "Error while decoding expression: no function matches the keys [nope]")
+
+	_, err = UnmarshalExpr([]byte(`{"add": [1`))
+	require.Error(t, err)
+
+	_, err = UnmarshalExpr([]byte(``))
+	require.Error(t, err)
+}
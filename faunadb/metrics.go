@@ -0,0 +1,199 @@
+package faunadb
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds of the latency histogram buckets used by Metrics when none are given.
+var DefaultLatencyBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// QueryMetrics holds the metrics aggregated for the queries sent with the same tags. See Tag.
+type QueryMetrics struct {
+	Tags          map[string]string
+	StatusCodes   map[int]int64 // number of queries by HTTP status code, 0 if no response was received
+	Latency       LatencyHistogram
+	ComputeOps    int64
+	ByteReadOps   int64
+	ByteWriteOps  int64
+	TxnRetries    int64 // transactions retried by FaunaDB, as reported in the x-txn-retries header
+	ClientRetries int64 // requests resent by the RetryPolicy, that is QueryResult.Attempts minus one
+}
+
+// LatencyHistogram is a histogram of query latencies. Counts[i] is the number of queries that took at most
+// Buckets[i], and Count is the total number of queries.
+type LatencyHistogram struct {
+	Buckets []time.Duration
+	Counts  []int64
+	Count   int64
+	Sum     time.Duration
+}
+
+// MetricsExporter receives the metrics aggregated by a FaunaClient. It is implemented by adapters to a metrics
+// library, such as Prometheus or expvar, so the driver does not depend on any of them.
+type MetricsExporter interface {
+	ExportQueryMetrics(metrics QueryMetrics)
+}
+
+// Metrics configures a FaunaClient to aggregate metrics for all of its queries, broken down by their tags:
+// the number of queries by status code, a latency histogram with the given bucket upper bounds, the totals of
+// compute, byte read and byte write ops and transaction retries reported by FaunaDB, and the total of requests
+// resent by the client RetryPolicy. Latencies include the retries. If no buckets are given,
+// DefaultLatencyBuckets is used. Clients created from this client share its metrics. Use ExportMetrics to read them.
+func Metrics(buckets ...time.Duration) ClientConfig {
+	return func(cli *FaunaClient) { cli.metrics = newMetricsCollector(buckets) }
+}
+
+// ExportMetrics sends the metrics aggregated by the client to the exporter, once for each set of tags, ordered
+// by tags. It does nothing if the client was not configured with Metrics.
+func (client *FaunaClient) ExportMetrics(exporter MetricsExporter) {
+	if client.metrics == nil {
+		return
+	}
+
+	for _, metrics := range client.metrics.snapshot() {
+		exporter.ExportQueryMetrics(metrics)
+	}
+}
+
+type metricsCollector struct {
+	buckets []time.Duration
+
+	mutex  sync.Mutex
+	series map[string]*QueryMetrics
+}
+
+func newMetricsCollector(buckets []time.Duration) *metricsCollector {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sorted := make([]time.Duration, len(buckets))
+	copy(sorted, buckets)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &metricsCollector{buckets: sorted, series: map[string]*QueryMetrics{}}
+}
+
+// record aggregates the outcome of a query request.
+func (collector *metricsCollector) record(request *Request, response *Response, err error, latency time.Duration) {
+	statusCode := 0
+	var stats QueryStats
+
+	if response != nil {
+		statusCode = response.StatusCode
+		stats = parseQueryStats(response.Headers)
+	}
+	if faunaErr, ok := err.(FaunaError); ok {
+		statusCode = faunaErr.Status()
+	}
+
+	key, tags := parseTags(request.Headers)
+
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	metrics, ok := collector.series[key]
+	if !ok {
+		metrics = &QueryMetrics{
+			Tags:        tags,
+			StatusCodes: map[int]int64{},
+			Latency: LatencyHistogram{
+				Buckets: collector.buckets,
+				Counts:  make([]int64, len(collector.buckets)),
+			},
+		}
+		collector.series[key] = metrics
+	}
+
+	metrics.StatusCodes[statusCode]++
+	metrics.ComputeOps += stats.ComputeOps
+	metrics.ByteReadOps += stats.ByteReadOps
+	metrics.ByteWriteOps += stats.ByteWriteOps
+	metrics.TxnRetries += stats.TxnRetries
+
+	if response != nil && response.attempts > 1 {
+		metrics.ClientRetries += int64(response.attempts - 1)
+	}
+
+	for i, bucket := range collector.buckets {
+		if latency <= bucket {
+			metrics.Latency.Counts[i]++
+		}
+	}
+	metrics.Latency.Count++
+	metrics.Latency.Sum += latency
+}
+
+// snapshot returns a copy of the aggregated metrics, ordered by tags.
+func (collector *metricsCollector) snapshot() []QueryMetrics {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	keys := make([]string, 0, len(collector.series))
+	for key := range collector.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	snapshot := make([]QueryMetrics, len(keys))
+	for i, key := range keys {
+		metrics := *collector.series[key]
+
+		metrics.Tags = make(map[string]string, len(metrics.Tags))
+		for k, v := range collector.series[key].Tags {
+			metrics.Tags[k] = v
+		}
+
+		metrics.StatusCodes = make(map[int]int64, len(metrics.StatusCodes))
+		for code, count := range collector.series[key].StatusCodes {
+			metrics.StatusCodes[code] = count
+		}
+
+		metrics.Latency.Counts = append([]int64(nil), metrics.Latency.Counts...)
+		snapshot[i] = metrics
+	}
+
+	return snapshot
+}
+
+// parseTags parses the tags header of a request. It returns the tags along with a key identifying them
+// regardless of their order.
+func parseTags(headers http.Header) (string, map[string]string) {
+	tags := map[string]string{}
+
+	for _, tag := range strings.Split(headers.Get(headerTags), ",") {
+		if tag == "" {
+			continue
+		}
+		kv := strings.SplitN(tag, "=", 2)
+		if len(kv) == 2 {
+			tags[kv[0]] = kv[1]
+		} else {
+			tags[kv[0]] = ""
+		}
+	}
+
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ","), tags
+}
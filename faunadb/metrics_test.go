@@ -0,0 +1,124 @@
+package faunadb
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type metricsRecorder struct {
+	metrics []QueryMetrics
+}
+
+func (recorder *metricsRecorder) ExportQueryMetrics(metrics QueryMetrics) {
+	recorder.metrics = append(recorder.metrics, metrics)
+}
+
+func exportMetrics(client *FaunaClient) []QueryMetrics {
+	recorder := &metricsRecorder{}
+	client.ExportMetrics(recorder)
+	return recorder.metrics
+}
+
+func TestMetricsByTags(t *testing.T) {
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(headerTags) == "tenant=bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors": [{"code": "invalid argument", "description": "Invalid"}]}`))
+			return
+		}
+		statsHandler(w, r)
+	}, Metrics())
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	_, err = client.Query(Add(40, 2), Tag("tenant", "acme"), Tag("app", "web"))
+	require.NoError(t, err)
+	_, err = client.Query(Add(40, 2), Tag("app", "web"), Tag("tenant", "acme"))
+	require.NoError(t, err)
+	_, err = client.Query(Add(40, 2), Tag("tenant", "bad"))
+	require.Error(t, err)
+
+	metrics := exportMetrics(client)
+	require.Len(t, metrics, 3)
+
+	require.Empty(t, metrics[0].Tags)
+	require.Equal(t, map[int]int64{http.StatusOK: 1}, metrics[0].StatusCodes)
+	require.Equal(t, int64(1), metrics[0].ComputeOps)
+
+	require.Equal(t, map[string]string{"app": "web", "tenant": "acme"}, metrics[1].Tags)
+	require.Equal(t, map[int]int64{http.StatusOK: 2}, metrics[1].StatusCodes)
+	require.Equal(t, int64(2), metrics[1].ComputeOps)
+	require.Equal(t, int64(4), metrics[1].ByteReadOps)
+	require.Equal(t, int64(6), metrics[1].ByteWriteOps)
+	require.Equal(t, int64(2), metrics[1].TxnRetries)
+	require.Equal(t, int64(2), metrics[1].Latency.Count)
+
+	require.Equal(t, map[string]string{"tenant": "bad"}, metrics[2].Tags)
+	require.Equal(t, map[int]int64{http.StatusBadRequest: 1}, metrics[2].StatusCodes)
+	require.Zero(t, metrics[2].ComputeOps)
+}
+
+func TestMetricsClientRetries(t *testing.T) {
+	handler, _ := failingHandler(2, 409, contendedTransactionBody)
+	client, closeServer := mockedClient(handler, RetryPolicy(fastRetries(3)), Metrics())
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+
+	metrics := exportMetrics(client)
+	require.Len(t, metrics, 1)
+	require.Equal(t, int64(2), metrics[0].ClientRetries)
+	require.Zero(t, metrics[0].TxnRetries)
+	require.Equal(t, map[int]int64{http.StatusOK: 1}, metrics[0].StatusCodes)
+}
+
+func TestMetricsLatencyHistogram(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(20*time.Millisecond), Metrics(time.Hour, 10*time.Millisecond))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+
+	metrics := exportMetrics(client)
+	require.Len(t, metrics, 1)
+
+	latency := metrics[0].Latency
+	require.Equal(t, []time.Duration{10 * time.Millisecond, time.Hour}, latency.Buckets)
+	require.Equal(t, []int64{0, 1}, latency.Counts)
+	require.Equal(t, int64(1), latency.Count)
+	require.True(t, latency.Sum >= 20*time.Millisecond)
+}
+
+func TestMetricsSharedWithDerivedClients(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(0), Metrics())
+	defer closeServer()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.NewSessionClient("secret").Query(Add(40, 2))
+		}()
+	}
+	wg.Wait()
+
+	metrics := exportMetrics(client)
+	require.Len(t, metrics, 1)
+	require.Equal(t, map[int]int64{http.StatusOK: 10}, metrics[0].StatusCodes)
+}
+
+func TestMetricsNotConfigured(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(0))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Empty(t, exportMetrics(client))
+}
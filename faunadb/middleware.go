@@ -0,0 +1,54 @@
+package faunadb
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Request describes a request to FaunaDB as seen by middlewares. Middlewares may replace the expression or
+// change the headers before calling the next RoundTrip.
+type Request struct {
+	Expr      Expr
+	Headers   http.Header
+	Streaming bool
+}
+
+// Response describes the response of FaunaDB to a Request. For queries, Value holds the query result.
+// For streams, Value is nil: the stream events are delivered by the subscription once connected.
+type Response struct {
+	StatusCode int
+	Headers    http.Header
+	Value      Value
+
+	stream   *faunaResponse
+	attempts int // requests sent for the query, including the ones retried by the RetryPolicy
+}
+
+// RoundTrip sends a Request to FaunaDB and returns its Response, or the error that ended the request. Queries
+// answered with an error response also return a Response, without Value, holding its status code and headers.
+type RoundTrip func(ctx context.Context, request *Request) (*Response, error)
+
+// MiddlewareFunc wraps a RoundTrip, typically calling next to continue the request.
+type MiddlewareFunc func(next RoundTrip) RoundTrip
+
+var errStreamResponse = errors.New("Stream request did not return a stream connection")
+
+// Middleware configures functions that wrap every request issued by a FaunaClient, for both queries and
+// stream subscriptions. Middlewares run in the given order, the first one being the outermost, and can
+// inspect or change the request, its response and error, or answer a query without calling next.
+// Stream requests must reach FaunaDB: their response must be the one returned by next.
+func Middleware(middlewares ...MiddlewareFunc) ClientConfig {
+	return func(cli *FaunaClient) {
+		cli.middlewares = append(cli.middlewares, middlewares...)
+	}
+}
+
+// roundTrip sends the request through the client middlewares, then through the given round trip.
+func (client *FaunaClient) roundTrip(ctx context.Context, request *Request, send RoundTrip) (*Response, error) {
+	for i := len(client.middlewares) - 1; i >= 0; i-- {
+		send = client.middlewares[i](send)
+	}
+
+	return send(ctx, request)
+}
@@ -0,0 +1,172 @@
+package faunadb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func recordingMiddleware(name string, calls *[]string) MiddlewareFunc {
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			*calls = append(*calls, name+" request")
+			response, err := next(ctx, request)
+			*calls = append(*calls, name+" response")
+			return response, err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+
+	client, closeServer := mockedClient(slowHandler(0), Middleware(
+		recordingMiddleware("first", &calls),
+		recordingMiddleware("second", &calls),
+	))
+	defer closeServer()
+
+	value, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+	require.Equal(t, []string{"first request", "second request", "second response", "first response"}, calls)
+}
+
+func TestMiddlewareSeesRequestAndResponse(t *testing.T) {
+	var seenRequest *Request
+	var seenResponse *Response
+
+	client, closeServer := mockedClient(slowHandler(0), Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			seenRequest = request
+			response, err := next(ctx, request)
+			seenResponse = response
+			return response, err
+		}
+	}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2), Tag("tenant", "acme"))
+	require.NoError(t, err)
+
+	require.Equal(t, Add(40, 2), seenRequest.Expr)
+	require.False(t, seenRequest.Streaming)
+	require.Equal(t, "tenant=acme", seenRequest.Headers.Get(headerTags))
+	require.NotEmpty(t, seenRequest.Headers.Get("Authorization"))
+
+	require.Equal(t, http.StatusOK, seenResponse.StatusCode)
+	require.Equal(t, LongV(42), seenResponse.Value)
+}
+
+func TestMiddlewareChangesRequest(t *testing.T) {
+	var body, authorization string
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		body, authorization = string(raw), r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}, Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			request.Expr = Add(1, 1)
+			request.Headers.Set("Authorization", "Bearer refreshed")
+			return next(ctx, request)
+		}
+	}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"add": [1, 1]}`, body)
+	require.Equal(t, "Bearer refreshed", authorization)
+}
+
+func TestMiddlewareAnswersWithoutNext(t *testing.T) {
+	requests := 0
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}, Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			return &Response{Value: StringV("cached")}, nil
+		}
+	}))
+	defer closeServer()
+
+	value, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, StringV("cached"), value)
+	require.Zero(t, requests)
+}
+
+func TestMiddlewareSeesErrors(t *testing.T) {
+	var seenErr error
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "invalid argument", "description": "Invalid"}]}`))
+	}, Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			response, err := next(ctx, request)
+			seenErr = err
+			return response, err
+		}
+	}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Error(t, err)
+	require.IsType(t, BadRequest{}, seenErr)
+}
+
+func TestMiddlewareStream(t *testing.T) {
+	var requests []*Request
+
+	server := &streamServer{connections: []streamConnection{
+		sendEvents(startEvent, versionEvent),
+		keepOpen(sendEvents(startEvent)),
+	}}
+
+	client, closeServer := mockedClient(server.ServeHTTP, Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			if request.Streaming {
+				requests = append(requests, request)
+			}
+			request.Headers.Set("X-Audit", "stream")
+			return next(ctx, request)
+		}
+	}))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, VersionEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, ReconnectEventT, nextEvent(t, &sub).Type())
+
+	sub.Close()
+	requireClosed(t, &sub)
+
+	require.Len(t, requests, 2)
+	require.True(t, requests[0].Streaming)
+	require.Equal(t, RefV{ID: "1"}, requests[0].Expr)
+	require.Equal(t, "2", requests[1].Headers.Get(headerLastSeenTxn))
+	require.Equal(t, "stream", server.received()[0].Header.Get("X-Audit"))
+}
+
+func TestMiddlewareStreamWithoutNext(t *testing.T) {
+	client, closeServer := mockedClient(slowHandler(0), Middleware(func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *Request) (*Response, error) {
+			return &Response{}, nil
+		}
+	}))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.Equal(t, errStreamResponse, sub.Start())
+	require.Equal(t, StreamConnError, sub.Status())
+}
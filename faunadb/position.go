@@ -0,0 +1,136 @@
+package faunadb
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// InvalidExprPosition describes an error that occurs when a query error position can not be found in
+// the expression that originated it.
+type InvalidExprPosition struct {
+	position []string
+	segment  string
+}
+
+func (i InvalidExprPosition) Error() string {
+	return fmt.Sprintf("Error while locating expression at: %s. Segment %q not found", positionString(i.position), i.segment)
+}
+
+// ExprAt returns the subexpression of the given query pointed by the error position. The query must be
+// the same expression that was sent to FaunaDB. For example:
+//
+//	query := f.Map(f.Paginate(f.Documents(f.Collection("users"))), f.Lambda("ref", f.Get(f.Var("ref"))))
+//
+//	if _, err := client.Query(query); err != nil {
+//		if faunaErr, ok := err.(f.FaunaError); ok {
+//			for _, queryErr := range faunaErr.Errors() {
+//				failed, _ := queryErr.ExprAt(query) // Returns f.Get(f.Var("ref")) for position [map expr]
+//			}
+//		}
+//	}
+func (queryError QueryError) ExprAt(query Expr) (Expr, error) {
+	return exprAt(query, queryError.Position)
+}
+
+// Describe returns a description of the query error including the subexpression that caused it.
+// If the error position can not be found in the given query, the description omits the subexpression.
+func (queryError QueryError) Describe(query Expr) string {
+	description := fmt.Sprintf("[%s](%s): %s", positionString(queryError.Position), queryError.Code, queryError.Description)
+
+	if expr, err := queryError.ExprAt(query); err == nil {
+		description += fmt.Sprintf(", at: %s", ExprString(expr))
+	}
+
+	return description
+}
+
+func exprAt(expr Expr, position []string) (Expr, error) {
+	current := expr
+
+	for i, segment := range position {
+		next, ok := exprChild(current, segment)
+		if !ok {
+			return nil, InvalidExprPosition{position[:i+1], segment}
+		}
+
+		current = next
+	}
+
+	return current, nil
+}
+
+func exprChild(expr Expr, segment string) (Expr, bool) {
+	switch e := expr.(type) {
+	case nil:
+		return nil, false
+
+	case unescapedObj:
+		child, ok := e[segment]
+		return child, ok
+
+	case ObjectV:
+		if segment != "object" {
+			return nil, false
+		}
+
+		obj := make(unescapedObj, len(e))
+		for key, value := range e {
+			obj[key] = value
+		}
+
+		return obj, true
+
+	case Obj, Arr:
+		return exprChild(wrap(e), segment)
+
+	case unescapedArr:
+		if index, ok := arrayIndex(segment, len(e)); ok {
+			return e[index], true
+		}
+
+	case ArrayV:
+		if index, ok := arrayIndex(segment, len(e)); ok {
+			return e[index], true
+		}
+
+	default:
+		return fnChild(e, segment)
+	}
+
+	return nil, false
+}
+
+func fnChild(expr Expr, segment string) (Expr, bool) {
+	value := reflect.ValueOf(expr)
+	if value.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	valueType := value.Type()
+
+	for i, size := 0, value.NumField(); i < size; i++ {
+		field := valueType.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.Anonymous || name != segment {
+			continue
+		}
+
+		if child, ok := value.Field(i).Interface().(Expr); ok && child != nil {
+			return child, true
+		}
+	}
+
+	return nil, false
+}
+
+func arrayIndex(segment string, length int) (int, bool) {
+	index, err := strconv.Atoi(segment)
+	return index, err == nil && index >= 0 && index < length
+}
+
+func positionString(position []string) string {
+	return strings.Join(position, "/")
+}
@@ -0,0 +1,81 @@
+package faunadb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExprAtFunctionArguments(t *testing.T) {
+	query := Map(
+		Paginate(MatchTerm(Index("users_by_email"), "a@b.c")),
+		Lambda("ref", Create(Collection("users"), Obj{"data": Obj{"email": Var("email")}})),
+	)
+
+	expr, err := QueryError{Position: []string{"map", "expr", "params", "object", "data", "object", "email"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, Var("email"), expr)
+
+	expr, err = QueryError{Position: []string{"collection", "paginate", "terms"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, StringV("a@b.c"), expr)
+
+	expr, err = QueryError{Position: []string{"map", "expr"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, Create(Collection("users"), Obj{"data": Obj{"email": Var("email")}}), expr)
+}
+
+func TestExprAtRoot(t *testing.T) {
+	expr, err := QueryError{}.ExprAt(Add(1, 2))
+	require.NoError(t, err)
+	require.Equal(t, Add(1, 2), expr)
+}
+
+func TestExprAtArrays(t *testing.T) {
+	query := Let().Bind("x", Arr{1, Select("a", Obj{})}).In(Add(Var("x"), 2))
+
+	expr, err := QueryError{Position: []string{"let", "0", "x", "1"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, Select("a", Obj{}), expr)
+
+	expr, err = QueryError{Position: []string{"in", "add", "1"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, LongV(2), expr)
+}
+
+func TestExprAtValues(t *testing.T) {
+	query := Update(RefV{ID: "1"}, ObjectV{"data": ArrayV{StringV("a"), StringV("b")}})
+
+	expr, err := QueryError{Position: []string{"params", "object", "data", "1"}}.ExprAt(query)
+	require.NoError(t, err)
+	require.Equal(t, StringV("b"), expr)
+}
+
+func TestExprAtInvalidPosition(t *testing.T) {
+	_, err := QueryError{Position: []string{"map", "lambda", "x"}}.ExprAt(Map(Arr{}, Lambda("x", Var("x"))))
+	require.EqualError(t, err, `Error while locating expression at: map/lambda/x. Segment "x" not found`)
+
+	_, err = QueryError{Position: []string{"add", "5"}}.ExprAt(Add(1, 2))
+	require.EqualError(t, err, `Error while locating expression at: add/5. Segment "5" not found`)
+}
+
+func TestDescribeQueryError(t *testing.T) {
+	query := Do(Get(Ref(Collection("users"), "1")), Select("name", Obj{}))
+	queryError := QueryError{
+		Position:    []string{"do", "1"},
+		Code:        "value not found",
+		Description: "Value not found at path [name].",
+	}
+
+	require.Equal(t,
+		`[do/1](value not found): Value not found at path [name]., at: Select("name", Obj{})`,
+		queryError.Describe(query),
+	)
+}
+
+func TestParseNumericErrorPositions(t *testing.T) {
+	json := `{"errors": [{"position": ["do", 1, "select"], "code": "value not found", "description": "not found"}]}`
+
+	err := checkForResponseErrors(httpErrorResponseWith(404, json)).(FaunaError)
+	require.Equal(t, []string{"do", "1", "select"}, err.Errors()[0].Position)
+}
@@ -0,0 +1,68 @@
+package faunadb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// QueryStats holds the statistics reported by FaunaDB in the response headers of a query.
+type QueryStats struct {
+	ComputeOps        int64         // X-Compute-Ops
+	ByteReadOps       int64         // X-Byte-Read-Ops
+	ByteWriteOps      int64         // X-Byte-Write-Ops
+	QueryTime         time.Duration // X-Query-Time
+	StorageBytesRead  int64         // X-Storage-Bytes-Read
+	StorageBytesWrite int64         // X-Storage-Bytes-Write
+	TxnRetries        int64         // X-Txn-Retries
+	QueryBytesIn      int64         // X-Query-Bytes-In
+	QueryBytesOut     int64         // X-Query-Bytes-Out
+	TxnTime           int64         // X-Txn-Time
+}
+
+// QueryWithStats sends a query language expression to FaunaDB, as Query does, and returns the statistics
+// of the query along with its result. Failed queries answered by FaunaDB, including the ones failed by a cost
+// budget, return their statistics along with the error.
+func (client *FaunaClient) QueryWithStats(expr Expr, configs ...QueryConfig) (value Value, stats QueryStats, err error) {
+	return client.QueryWithStatsContext(context.Background(), expr, configs...)
+}
+
+// QueryWithStatsContext is QueryWithStats using the provided context, as QueryContext does.
+func (client *FaunaClient) QueryWithStatsContext(ctx context.Context, expr Expr, configs ...QueryConfig) (value Value, stats QueryStats, err error) {
+	var response *Response
+
+	response, err = client.queryResponse(ctx, SpanQuery, expr, configs)
+
+	if response != nil {
+		stats = parseQueryStats(response.Headers)
+		if err == nil {
+			value = response.Value
+		}
+	}
+
+	return
+}
+
+// parseQueryStats reads the statistics headers. Missing or invalid headers are left as zero.
+func parseQueryStats(header http.Header) (stats QueryStats) {
+	for name, stat := range map[string]*int64{
+		headerComputeOps:        &stats.ComputeOps,
+		headerByteReadOps:       &stats.ByteReadOps,
+		headerByteWriteOps:      &stats.ByteWriteOps,
+		headerStorageBytesRead:  &stats.StorageBytesRead,
+		headerStorageBytesWrite: &stats.StorageBytesWrite,
+		headerTxnRetries:        &stats.TxnRetries,
+		headerQueryBytesIn:      &stats.QueryBytesIn,
+		headerQueryBytesOut:     &stats.QueryBytesOut,
+		headerTxnTime:           &stats.TxnTime,
+	} {
+		*stat, _ = strconv.ParseInt(header.Get(name), 10, 64)
+	}
+
+	if millis, err := strconv.ParseInt(header.Get(headerQueryTime), 10, 64); err == nil {
+		stats.QueryTime = time.Duration(millis) * time.Millisecond
+	}
+
+	return
+}
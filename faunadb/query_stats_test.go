@@ -0,0 +1,76 @@
+package faunadb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func statsHandler(w http.ResponseWriter, r *http.Request) {
+	for header, value := range map[string]string{
+		headerComputeOps:        "1",
+		headerByteReadOps:       "2",
+		headerByteWriteOps:      "3",
+		headerQueryTime:         "15",
+		headerStorageBytesRead:  "256",
+		headerStorageBytesWrite: "512",
+		headerTxnRetries:        "1",
+		headerQueryBytesIn:      "24",
+		headerQueryBytesOut:     "48",
+		headerTxnTime:           "1616161616161616",
+	} {
+		w.Header().Set(header, value)
+	}
+	_, _ = w.Write([]byte(`{"resource": 42}`))
+}
+
+var expectedStats = QueryStats{
+	ComputeOps:        1,
+	ByteReadOps:       2,
+	ByteWriteOps:      3,
+	QueryTime:         15 * time.Millisecond,
+	StorageBytesRead:  256,
+	StorageBytesWrite: 512,
+	TxnRetries:        1,
+	QueryBytesIn:      24,
+	QueryBytesOut:     48,
+	TxnTime:           1616161616161616,
+}
+
+func TestQueryWithStats(t *testing.T) {
+	client, closeServer := mockedClient(statsHandler)
+	defer closeServer()
+
+	value, stats, err := client.QueryWithStats(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+	require.Equal(t, expectedStats, stats)
+}
+
+func TestQueryResultStats(t *testing.T) {
+	var stats QueryStats
+
+	client, closeServer := mockedClient(statsHandler, Observer(func(result *QueryResult) {
+		stats = result.Stats
+	}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, expectedStats, stats)
+}
+
+func TestQueryStatsIgnoreMissingAndInvalidHeaders(t *testing.T) {
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerComputeOps, "many")
+		w.Header().Set(headerByteReadOps, "7")
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	})
+	defer closeServer()
+
+	_, stats, err := client.QueryWithStats(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, QueryStats{ByteReadOps: 7}, stats)
+}
@@ -1,44 +1,231 @@
 package faunadb
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
 
-func structToMap(aStruct reflect.Value) map[string]interface{} {
-	res := make(map[string]interface{}, aStruct.NumField())
+// structField describes a struct field encoded to or decoded from a FaunaDB object, possibly promoted from an
+// embedded struct.
+type structField struct {
+	name      string       // key of the field in FaunaDB objects
+	goName    string       // name of the Go field, used in decoding error paths
+	index     []int        // index sequence for reflect.Value.FieldByIndex
+	typ       reflect.Type // type of the field, or of the embedded struct while collecting fields
+	tagged    bool
+	omitempty bool
+}
+
+// structFields describes how a struct type is encoded to and decoded from a FaunaDB object.
+type structFields struct {
+	list   []structField
+	inline *structField // map collecting the keys that do not match any field
+}
+
+// typeFields returns the fields of the given struct type. Embedded structs, and struct fields with the inline
+// option, are flattened following the encoding/json rules: among fields with the same name, the shallowest one
+// is used, and a tagged field takes precedence over untagged ones at the same depth. Fields with a name conflict
+// that can not be resolved are ignored. Fields with invalid tags are skipped and the first tag error is returned
+// along with the remaining fields.
+func typeFields(t reflect.Type) (fields structFields, err error) {
+	var current []structField
+	next := []structField{{typ: t}}
+
+	var count, nextCount map[reflect.Type]int
+	visited := map[reflect.Type]bool{}
+
+	var inlines []structField
+
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				fieldType := sf.Type
+				if fieldType.Name() == "" && fieldType.Kind() == reflect.Ptr {
+					fieldType = fieldType.Elem()
+				}
+
+				if sf.Anonymous {
+					if !sf.IsExported() && fieldType.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !sf.IsExported() {
+					continue
+				}
+
+				tag, tagErr := parseTag(sf)
+				if tagErr != nil {
+					if err == nil {
+						err = fmt.Errorf("%w on field %s of %s", tagErr, sf.Name, f.typ)
+					}
+					continue
+				}
+
+				if tag.ignore {
+					continue
+				}
+
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
 
-	if expStructFields, err := exportedStructFields(aStruct); err == nil {
-		for key, value := range expStructFields {
-			res[key] = value.Interface()
+				flatten := fieldType.Kind() == reflect.Struct && !isOpaqueStruct(fieldType) &&
+					(tag.inline || (sf.Anonymous && !tag.named))
+
+				if flatten {
+					nextCount[fieldType]++
+					if nextCount[fieldType] == 1 {
+						next = append(next, structField{name: fieldType.Name(), index: index, typ: fieldType})
+					}
+					continue
+				}
+
+				field := structField{
+					name:      tag.name,
+					goName:    sf.Name,
+					index:     index,
+					typ:       sf.Type,
+					tagged:    tag.named,
+					omitempty: tag.omitempty,
+				}
+
+				if tag.inline {
+					if fieldType.Kind() == reflect.Struct {
+						if err == nil {
+							err = fmt.Errorf("fauna: inline option can not be used on field %s, %s is opaque: it is encoded as a single value", sf.Name, sf.Type)
+						}
+						continue
+					}
+					if sf.Type.Kind() != reflect.Map || sf.Type.Key().Kind() != reflect.String {
+						if err == nil {
+							err = fmt.Errorf("fauna: inline option requires a struct or a map with string keys, but field %s is a %s", sf.Name, sf.Type)
+						}
+						continue
+					}
+					inlines = append(inlines, field)
+					continue
+				}
+
+				fields.list = append(fields.list, field)
+				if count[f.typ] > 1 {
+					// The same struct is embedded more than once at this depth, its fields annihilate each other
+					fields.list = append(fields.list, field)
+				}
+			}
 		}
 	}
-	return res
+
+	fields.list = dominantFields(fields.list)
+
+	if len(inlines) == 1 || (len(inlines) > 1 && len(inlines[0].index) < len(inlines[1].index)) {
+		fields.inline = &inlines[0]
+	} else if len(inlines) > 1 && err == nil {
+		err = fmt.Errorf("fauna: struct %s has more than one inline map", t)
+	}
+
+	return
 }
 
-func exportedStructFields(aStruct reflect.Value) (map[string]reflect.Value, error) {
-	fields := make(map[string]reflect.Value)
-	aStructType := aStruct.Type()
+// isOpaqueStruct reports whether the struct type is encoded as a single value, such as time.Time and the
+// Value types, so its fields can not be flattened.
+func isOpaqueStruct(t reflect.Type) bool {
+	return t == timeType || t.Implements(valueType) || reflect.PtrTo(t).Implements(valueType)
+}
 
-	for i, size := 0, aStruct.NumField(); i < size; i++ {
-		field := aStruct.Field(i)
+type cachedFields struct {
+	fields structFields
+	err    error
+}
 
-		if !field.CanInterface() {
-			continue
+var fieldCache sync.Map // map[reflect.Type]cachedFields
+
+// cachedTypeFields is like typeFields but computes the fields of each struct type only once. Tag errors are
+// cached along with the fields and reported on every use of the type.
+func cachedTypeFields(t reflect.Type) (structFields, error) {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.(cachedFields).fields, cached.(cachedFields).err
+	}
+
+	fields, err := typeFields(t)
+	cached, _ := fieldCache.LoadOrStore(t, cachedFields{fields, err})
+	return cached.(cachedFields).fields, cached.(cachedFields).err
+}
+
+// dominantFields resolves name conflicts among fields and returns the remaining fields in index order.
+func dominantFields(fields []structField) []structField {
+	sort.Slice(fields, func(i, j int) bool {
+		if fields[i].name != fields[j].name {
+			return fields[i].name < fields[j].name
+		}
+		if len(fields[i].index) != len(fields[j].index) {
+			return len(fields[i].index) < len(fields[j].index)
 		}
+		if fields[i].tagged != fields[j].tagged {
+			return fields[i].tagged
+		}
+		return indexLess(fields[i].index, fields[j].index)
+	})
 
-		fieldName, ignore, omitempty, err := parseTag(aStructType.Field(i))
-		if err != nil {
-			return nil, err
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fields[i].name {
+				break
+			}
 		}
 
-		if omitempty && isEmptyValue(field) {
+		dominant := fields[i]
+		if advance > 1 && len(dominant.index) == len(fields[i+1].index) && dominant.tagged == fields[i+1].tagged {
 			continue
 		}
+		out = append(out, dominant)
+	}
 
-		if !ignore && fieldName != "" {
-			fields[fieldName] = field
+	sort.Slice(out, func(i, j int) bool { return indexLess(out[i].index, out[j].index) })
+	return out
+}
+
+func indexLess(a, b []int) bool {
+	for k, i := range a {
+		if k >= len(b) {
+			return false
+		}
+		if i != b[k] {
+			return i < b[k]
 		}
 	}
+	return len(a) < len(b)
+}
 
-	return fields, nil
+// fieldByIndex returns the struct field at the given index sequence. Nil embedded pointers are allocated if
+// alloc is true, otherwise, or if they can not be set, it returns false.
+func fieldByIndex(aStruct reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	value := aStruct
+
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !alloc || !value.CanSet() {
+					return reflect.Value{}, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+
+	return value, true
 }
 
 func indirectValue(i interface{}) (reflect.Value, reflect.Type) {
@@ -77,26 +264,3 @@ func indirectValue(i interface{}) (reflect.Value, reflect.Type) {
 
 	return value, value.Type()
 }
-
-func allStructFields(aStruct reflect.Value) map[string]reflect.Value {
-	fields := make(map[string]reflect.Value)
-	aStructType := aStruct.Type()
-
-	for i, size := 0, aStruct.NumField(); i < size; i++ {
-		field := aStruct.Field(i)
-		structTypeField := aStructType.Field(i)
-
-		if !field.CanInterface() {
-			continue
-		}
-
-		fieldName, ignore, _, _ := parseTag(aStructType.Field(i))
-
-		if !ignore && fieldName != "" {
-			fields[fieldName] = field
-		}
-		fields[structTypeField.Name] = field
-	}
-
-	return fields
-}
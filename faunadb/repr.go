@@ -0,0 +1,692 @@
+package faunadb
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const reprTag = "faunarepr"
+
+/*
+ExprString renders an expression using the driver's query language syntax. For example:
+
+	ExprString(Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), Size(10)))
+
+Returns:
+
+	Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), Size(10))
+
+All expressions and values also implement fmt.Stringer using the same representation.
+*/
+func ExprString(expr Expr) string {
+	var sb strings.Builder
+	writeExpr(&sb, expr)
+	return sb.String()
+}
+
+// fnRepr describes how a function structure maps to its query language function call.
+type fnRepr struct {
+	name     string
+	args     []argRepr
+	optional []argRepr
+}
+
+type argRepr struct {
+	index   int
+	name    string
+	varargs bool
+	scoped  bool
+	noargs  bool
+}
+
+// fnOverride describes functions whose name or arguments order differ from their structure definition.
+type fnOverride struct {
+	name string
+	args []string
+}
+
+var (
+	fnReprs     sync.Map
+	fnOverrides = map[reflect.Type]fnOverride{
+		reflect.TypeOf(mapFn{}):       {args: []string{"Collection", "Map"}},
+		reflect.TypeOf(foreachFn{}):   {args: []string{"Collection", "Foreach"}},
+		reflect.TypeOf(filterFn{}):    {args: []string{"Collection", "Filter"}},
+		reflect.TypeOf(powFn{}):       {name: "Pow", args: []string{"Pow", "Exp"}},
+		reflect.TypeOf(refFn{}):       {name: "RefCollection"},
+		reflect.TypeOf(lowercaseFn{}): {name: "LowerCase"},
+		reflect.TypeOf(titleCaseFn{}): {name: "TitleCase"},
+	}
+)
+
+// parseReprTag interprets faunarepr struct field tags. Options are either flags, such as "varargs",
+// or key value pairs, such as "name=EventsOpt". The "fn=optfn" pair is equivalent to the "optfn" flag.
+func parseReprTag(field reflect.StructField) map[string]string {
+	options := make(map[string]string)
+
+	for _, part := range strings.Split(field.Tag.Get(reprTag), ",") {
+		if part == "" {
+			continue
+		}
+
+		if kv := strings.SplitN(part, "=", 2); len(kv) == 2 {
+			if kv[0] == "fn" {
+				options[kv[1]] = "true"
+			} else {
+				options[kv[0]] = kv[1]
+			}
+		} else {
+			options[part] = "true"
+		}
+	}
+
+	return options
+}
+
+func reprOf(fnType reflect.Type) *fnRepr {
+	if repr, ok := fnReprs.Load(fnType); ok {
+		return repr.(*fnRepr)
+	}
+
+	repr := &fnRepr{}
+	override := fnOverrides[fnType]
+	byName := make(map[string]argRepr)
+
+	for i, size := 0, fnType.NumField(); i < size; i++ {
+		field := fnType.Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		options := parseReprTag(field)
+		arg := argRepr{
+			index:   i,
+			name:    field.Name,
+			varargs: options["varargs"] == "true",
+			scoped:  options["scopedfn"] == "true",
+			noargs:  options["noargs"] == "true",
+		}
+
+		if name, ok := options["name"]; ok {
+			arg.name = name
+		}
+
+		if repr.name == "" {
+			repr.name = field.Name
+		}
+
+		if options["optfn"] == "true" {
+			repr.optional = append(repr.optional, arg)
+		} else {
+			repr.args = append(repr.args, arg)
+			byName[field.Name] = arg
+		}
+	}
+
+	if override.name != "" {
+		repr.name = override.name
+	}
+
+	if len(override.args) > 0 {
+		repr.args = repr.args[:0]
+		for _, name := range override.args {
+			repr.args = append(repr.args, byName[name])
+		}
+	}
+
+	fnReprs.Store(fnType, repr)
+	return repr
+}
+
+func writeExpr(sb *strings.Builder, expr Expr) {
+	switch e := expr.(type) {
+	case nil, NullV:
+		sb.WriteString("Null()")
+	case StringV:
+		sb.WriteString(strconv.Quote(string(e)))
+	case LongV:
+		sb.WriteString(strconv.FormatInt(int64(e), 10))
+	case DoubleV:
+		sb.WriteString(formatDouble(float64(e)))
+	case BooleanV:
+		sb.WriteString(strconv.FormatBool(bool(e)))
+	case DateV:
+		writeCall(sb, "Date", StringV(time.Time(e).Format("2006-01-02")))
+	case TimeV:
+		writeCall(sb, "Time", StringV(time.Time(e).UTC().Format("2006-01-02T15:04:05.999999999Z")))
+	case RefV:
+		writeRef(sb, e)
+	case *RefV:
+		writeRef(sb, *e)
+	case SetRefV:
+		writeSetRef(sb, e)
+	case BytesV:
+		writeBytes(sb, e)
+	case QueryV:
+		writeQuery(sb, e)
+	case ObjectV:
+		writeObject(sb, objectKeys(e), func(key string) Expr { return e[key] })
+	case ArrayV:
+		writeArray(sb, len(e), func(i int) Expr { return e[i] })
+	case Obj, Arr:
+		writeExpr(sb, wrap(e))
+	case unescapedObj:
+		if obj, ok := e["object"].(unescapedObj); ok && len(e) == 1 {
+			e = obj
+		}
+		writeObject(sb, objectKeys(e), func(key string) Expr { return e[key] })
+	case unescapedArr:
+		writeArray(sb, len(e), func(i int) Expr { return e[i] })
+	case letFn:
+		writeLet(sb, e)
+	case matchFn:
+		if e.Terms != nil {
+			writeCall(sb, "MatchTerm", e.Match, e.Terms)
+		} else {
+			writeCall(sb, "Match", e.Match)
+		}
+	case invalidExpr:
+		sb.WriteString(fmt.Sprintf("Invalid(%q)", e.err))
+	default:
+		writeFn(sb, e)
+	}
+}
+
+func writeFn(sb *strings.Builder, expr Expr) {
+	value := reflect.ValueOf(expr)
+	if value.Kind() != reflect.Struct {
+		sb.WriteString(fmt.Sprintf("%v", expr))
+		return
+	}
+
+	repr := reprOf(value.Type())
+	name := repr.name
+	var args []Expr
+
+	for _, arg := range repr.args {
+		field, _ := value.Field(arg.index).Interface().(Expr)
+
+		switch {
+		case arg.noargs, field == nil:
+			continue
+		case arg.scoped:
+			if _, isNull := field.(NullV); !isNull {
+				name = "Scoped" + name
+				args = append(args, field)
+			}
+		case arg.varargs:
+			if arr, ok := field.(unescapedArr); ok {
+				args = append(args, arr...)
+			} else {
+				args = append(args, field)
+			}
+		default:
+			args = append(args, field)
+		}
+	}
+
+	sb.WriteString(name)
+	sb.WriteString("(")
+
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeExpr(sb, arg)
+	}
+
+	for _, opt := range repr.optional {
+		field, _ := value.Field(opt.index).Interface().(Expr)
+		if field == nil {
+			continue
+		}
+
+		if len(args) > 0 {
+			sb.WriteString(", ")
+		}
+
+		if opt.noargs {
+			writeCall(sb, opt.name)
+		} else {
+			writeCall(sb, opt.name, field)
+		}
+
+		args = append(args, field)
+	}
+
+	sb.WriteString(")")
+}
+
+func writeCall(sb *strings.Builder, name string, args ...Expr) {
+	sb.WriteString(name)
+	sb.WriteString("(")
+
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeExpr(sb, arg)
+	}
+
+	sb.WriteString(")")
+}
+
+func writeLet(sb *strings.Builder, let letFn) {
+	bindings, ok := let.Let.(unescapedArr)
+	if !ok {
+		writeCall(sb, "Let", let.Let, let.In)
+		return
+	}
+
+	sb.WriteString("Let()")
+
+	for _, binding := range bindings {
+		if obj, ok := binding.(unescapedObj); ok {
+			for _, key := range objectKeys(obj) {
+				writeCall(sb, ".Bind", StringV(key), obj[key])
+			}
+		}
+	}
+
+	writeCall(sb, ".In", let.In)
+}
+
+func writeRef(sb *strings.Builder, ref RefV) {
+	var scope []Expr
+	prefix := ""
+
+	if ref.Database != nil {
+		scope = append(scope, *ref.Database)
+		prefix = "Scoped"
+	}
+
+	if ref.Collection == nil {
+		if name, ok := nativeRefFunctions[ref.ID]; ok {
+			writeCall(sb, prefix+name, scope...)
+		} else {
+			writeCall(sb, "Ref", StringV(ref.ID))
+		}
+		return
+	}
+
+	if ref.Collection.Collection == nil && ref.Collection.Database == nil {
+		if name, ok := nativeDocumentFunctions[ref.Collection.ID]; ok {
+			if name == "" {
+				writeCall(sb, "RefCollection", RefV{ID: ref.Collection.ID, Database: ref.Database}, StringV(ref.ID))
+			} else {
+				writeCall(sb, prefix+name, append([]Expr{StringV(ref.ID)}, scope...)...)
+			}
+			return
+		}
+	}
+
+	writeCall(sb, "RefCollection", *ref.Collection, StringV(ref.ID))
+}
+
+var (
+	nativeRefFunctions = map[string]string{
+		"classes":     "Classes",
+		"collections": "Collections",
+		"indexes":     "Indexes",
+		"databases":   "Databases",
+		"functions":   "Functions",
+		"roles":       "Roles",
+		"keys":        "Keys",
+		"tokens":      "Tokens",
+		"credentials": "Credentials",
+
+		"access_providers": "AccessProviders",
+	}
+
+	nativeDocumentFunctions = map[string]string{
+		"classes":          "Class",
+		"collections":      "Collection",
+		"indexes":          "Index",
+		"databases":        "Database",
+		"functions":        "Function",
+		"roles":            "Role",
+		"access_providers": "AccessProvider",
+
+		// No function builds the documents of these collections: their refs are built from the collection
+		"keys":        "",
+		"tokens":      "",
+		"credentials": "",
+	}
+)
+
+// writeSetRef renders a set by the expression that builds it, such as Match(Index("users")).
+func writeSetRef(sb *strings.Builder, set SetRefV) {
+	buffer, err := json.Marshal(set.Parameters)
+	if err == nil {
+		var expr Expr
+		if expr, err = UnmarshalExpr(buffer); err == nil {
+			writeExpr(sb, expr)
+			return
+		}
+	}
+
+	writeExpr(sb, invalidExpr{err})
+}
+
+// writeQuery renders a query value as the Query call of its lambda.
+func writeQuery(sb *strings.Builder, query QueryV) {
+	var fields map[string]json.RawMessage
+	err := json.Unmarshal(query.lambda, &fields)
+
+	if err == nil {
+		// FaunaDB tags stored lambdas with the API version they were written in
+		delete(fields, "api_version")
+
+		var buffer []byte
+		if buffer, err = json.Marshal(fields); err == nil {
+			var lambda Expr
+			if lambda, err = UnmarshalExpr(buffer); err == nil {
+				writeCall(sb, "Query", lambda)
+				return
+			}
+		}
+	}
+
+	writeExpr(sb, invalidExpr{err})
+}
+
+func writeBytes(sb *strings.Builder, bytes BytesV) {
+	sb.WriteString("BytesV{")
+
+	for i, b := range bytes {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(fmt.Sprintf("0x%02x", b))
+	}
+
+	sb.WriteString("}")
+}
+
+func writeObject(sb *strings.Builder, keys []string, get func(string) Expr) {
+	sb.WriteString("Obj{")
+
+	for i, key := range keys {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(strconv.Quote(key))
+		sb.WriteString(": ")
+		writeExpr(sb, get(key))
+	}
+
+	sb.WriteString("}")
+}
+
+func writeArray(sb *strings.Builder, size int, get func(int) Expr) {
+	sb.WriteString("Arr{")
+
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		writeExpr(sb, get(i))
+	}
+
+	sb.WriteString("}")
+}
+
+func objectKeys(obj interface{}) []string {
+	value := reflect.ValueOf(obj)
+	keys := make([]string, 0, value.Len())
+
+	for _, key := range value.MapKeys() {
+		keys = append(keys, key.String())
+	}
+
+	sort.Strings(keys)
+	return keys
+}
+
+func formatDouble(num float64) string {
+	format := byte('f')
+	if abs := math.Abs(num); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'g'
+	}
+
+	str := strconv.FormatFloat(num, format, -1, 64)
+
+	if !strings.ContainsAny(str, ".eEIN") {
+		str += ".0"
+	}
+
+	return str
+}
+
+// Implement fmt.Stringer for all expressions
+
+func (obj unescapedObj) String() string { return ExprString(obj) }
+func (arr unescapedArr) String() string { return ExprString(arr) }
+func (inv invalidExpr) String() string  { return ExprString(inv) }
+func (obj Obj) String() string          { return ExprString(obj) }
+func (arr Arr) String() string          { return ExprString(arr) }
+
+func (str StringV) String() string      { return ExprString(str) }
+func (num LongV) String() string        { return ExprString(num) }
+func (num DoubleV) String() string      { return ExprString(num) }
+func (boolean BooleanV) String() string { return ExprString(boolean) }
+func (date DateV) String() string       { return ExprString(date) }
+func (localTime TimeV) String() string  { return ExprString(localTime) }
+func (ref RefV) String() string         { return ExprString(ref) }
+func (set SetRefV) String() string      { return ExprString(set) }
+func (obj ObjectV) String() string      { return ExprString(obj) }
+func (arr ArrayV) String() string       { return ExprString(arr) }
+func (null NullV) String() string       { return ExprString(null) }
+func (bytes BytesV) String() string     { return ExprString(bytes) }
+func (query QueryV) String() string     { return ExprString(query) }
+
+func (fn loginFn) String() string                { return ExprString(fn) }
+func (fn logoutFn) String() string               { return ExprString(fn) }
+func (fn identifyFn) String() string             { return ExprString(fn) }
+func (fn identityFn) String() string             { return ExprString(fn) }
+func (fn hasIdentityFn) String() string          { return ExprString(fn) }
+func (fn abortFn) String() string                { return ExprString(fn) }
+func (fn doFn) String() string                   { return ExprString(fn) }
+func (fn ifFn) String() string                   { return ExprString(fn) }
+func (fn lambdaFn) String() string               { return ExprString(fn) }
+func (fn atFn) String() string                   { return ExprString(fn) }
+func (fn letFn) String() string                  { return ExprString(fn) }
+func (fn varFn) String() string                  { return ExprString(fn) }
+func (fn callFn) String() string                 { return ExprString(fn) }
+func (fn queryFn) String() string                { return ExprString(fn) }
+func (fn selectFn) String() string               { return ExprString(fn) }
+func (fn selectAllFn) String() string            { return ExprString(fn) }
+func (fn mapFn) String() string                  { return ExprString(fn) }
+func (fn foreachFn) String() string              { return ExprString(fn) }
+func (fn filterFn) String() string               { return ExprString(fn) }
+func (fn takeFn) String() string                 { return ExprString(fn) }
+func (fn dropFn) String() string                 { return ExprString(fn) }
+func (fn prependFn) String() string              { return ExprString(fn) }
+func (fn appendFn) String() string               { return ExprString(fn) }
+func (fn isEmptyFn) String() string              { return ExprString(fn) }
+func (fn isNonEmptyFn) String() string           { return ExprString(fn) }
+func (fn containsFn) String() string             { return ExprString(fn) }
+func (fn containsPathFn) String() string         { return ExprString(fn) }
+func (fn containsValueFn) String() string        { return ExprString(fn) }
+func (fn containsFieldFn) String() string        { return ExprString(fn) }
+func (fn countFn) String() string                { return ExprString(fn) }
+func (fn sumFn) String() string                  { return ExprString(fn) }
+func (fn meanFn) String() string                 { return ExprString(fn) }
+func (fn reverseFn) String() string              { return ExprString(fn) }
+func (fn timeFn) String() string                 { return ExprString(fn) }
+func (fn timeAddFn) String() string              { return ExprString(fn) }
+func (fn timeSubtractFn) String() string         { return ExprString(fn) }
+func (fn timeDiffFn) String() string             { return ExprString(fn) }
+func (fn dateFn) String() string                 { return ExprString(fn) }
+func (fn epochFn) String() string                { return ExprString(fn) }
+func (fn nowFn) String() string                  { return ExprString(fn) }
+func (fn toSecondsFn) String() string            { return ExprString(fn) }
+func (fn toMillisFn) String() string             { return ExprString(fn) }
+func (fn toMicrosFn) String() string             { return ExprString(fn) }
+func (fn yearFn) String() string                 { return ExprString(fn) }
+func (fn monthFn) String() string                { return ExprString(fn) }
+func (fn hourFn) String() string                 { return ExprString(fn) }
+func (fn minuteFn) String() string               { return ExprString(fn) }
+func (fn secondFn) String() string               { return ExprString(fn) }
+func (fn dayOfMonthFn) String() string           { return ExprString(fn) }
+func (fn dayOfWeekFn) String() string            { return ExprString(fn) }
+func (fn dayOfYearFn) String() string            { return ExprString(fn) }
+func (fn equalsFn) String() string               { return ExprString(fn) }
+func (fn anyFn) String() string                  { return ExprString(fn) }
+func (fn allFn) String() string                  { return ExprString(fn) }
+func (fn ltFn) String() string                   { return ExprString(fn) }
+func (fn lteFn) String() string                  { return ExprString(fn) }
+func (fn gtFn) String() string                   { return ExprString(fn) }
+func (fn gteFn) String() string                  { return ExprString(fn) }
+func (fn andFn) String() string                  { return ExprString(fn) }
+func (fn orFn) String() string                   { return ExprString(fn) }
+func (fn notFn) String() string                  { return ExprString(fn) }
+func (fn absFn) String() string                  { return ExprString(fn) }
+func (fn acosFn) String() string                 { return ExprString(fn) }
+func (fn asinFn) String() string                 { return ExprString(fn) }
+func (fn atanFn) String() string                 { return ExprString(fn) }
+func (fn addFn) String() string                  { return ExprString(fn) }
+func (fn bitAndFn) String() string               { return ExprString(fn) }
+func (fn bitNotFn) String() string               { return ExprString(fn) }
+func (fn bitOrFn) String() string                { return ExprString(fn) }
+func (fn bitXorFn) String() string               { return ExprString(fn) }
+func (fn ceilFn) String() string                 { return ExprString(fn) }
+func (fn cosFn) String() string                  { return ExprString(fn) }
+func (fn coshFn) String() string                 { return ExprString(fn) }
+func (fn degreesFn) String() string              { return ExprString(fn) }
+func (fn divideFn) String() string               { return ExprString(fn) }
+func (fn expFn) String() string                  { return ExprString(fn) }
+func (fn floorFn) String() string                { return ExprString(fn) }
+func (fn hypotFn) String() string                { return ExprString(fn) }
+func (fn lnFn) String() string                   { return ExprString(fn) }
+func (fn logFn) String() string                  { return ExprString(fn) }
+func (fn maxFn) String() string                  { return ExprString(fn) }
+func (fn minFn) String() string                  { return ExprString(fn) }
+func (fn moduloFn) String() string               { return ExprString(fn) }
+func (fn multiplyFn) String() string             { return ExprString(fn) }
+func (fn powFn) String() string                  { return ExprString(fn) }
+func (fn radiansFn) String() string              { return ExprString(fn) }
+func (fn roundFn) String() string                { return ExprString(fn) }
+func (fn signFn) String() string                 { return ExprString(fn) }
+func (fn sinFn) String() string                  { return ExprString(fn) }
+func (fn sinhFn) String() string                 { return ExprString(fn) }
+func (fn sqrtFn) String() string                 { return ExprString(fn) }
+func (fn subtractFn) String() string             { return ExprString(fn) }
+func (fn tanFn) String() string                  { return ExprString(fn) }
+func (fn tanhFn) String() string                 { return ExprString(fn) }
+func (fn truncFn) String() string                { return ExprString(fn) }
+func (fn getFn) String() string                  { return ExprString(fn) }
+func (fn keyFromSecretFn) String() string        { return ExprString(fn) }
+func (fn existsFn) String() string               { return ExprString(fn) }
+func (fn paginateFn) String() string             { return ExprString(fn) }
+func (fn legacyRefFn) String() string            { return ExprString(fn) }
+func (fn refFn) String() string                  { return ExprString(fn) }
+func (fn databaseFn) String() string             { return ExprString(fn) }
+func (fn indexFn) String() string                { return ExprString(fn) }
+func (fn classFn) String() string                { return ExprString(fn) }
+func (fn collectionFn) String() string           { return ExprString(fn) }
+func (fn documentsFn) String() string            { return ExprString(fn) }
+func (fn functionFn) String() string             { return ExprString(fn) }
+func (fn roleFn) String() string                 { return ExprString(fn) }
+func (fn classesFn) String() string              { return ExprString(fn) }
+func (fn collectionsFn) String() string          { return ExprString(fn) }
+func (fn indexesFn) String() string              { return ExprString(fn) }
+func (fn databasesFn) String() string            { return ExprString(fn) }
+func (fn functionsFn) String() string            { return ExprString(fn) }
+func (fn rolesFn) String() string                { return ExprString(fn) }
+func (fn keysFn) String() string                 { return ExprString(fn) }
+func (fn tokensFn) String() string               { return ExprString(fn) }
+func (fn credentialsFn) String() string          { return ExprString(fn) }
+func (fn nextIDFn) String() string               { return ExprString(fn) }
+func (fn newIDFn) String() string                { return ExprString(fn) }
+func (fn accessProviderFn) String() string       { return ExprString(fn) }
+func (fn accessProvidersFn) String() string      { return ExprString(fn) }
+func (fn currentIdentityFn) String() string      { return ExprString(fn) }
+func (fn currentTokenFn) String() string         { return ExprString(fn) }
+func (fn hasCurrentIdentityFn) String() string   { return ExprString(fn) }
+func (fn hasCurrentTokenFn) String() string      { return ExprString(fn) }
+func (fn singletonFn) String() string            { return ExprString(fn) }
+func (fn eventsFn) String() string               { return ExprString(fn) }
+func (fn matchFn) String() string                { return ExprString(fn) }
+func (fn unionFn) String() string                { return ExprString(fn) }
+func (fn mergeFn) String() string                { return ExprString(fn) }
+func (fn reduceFn) String() string               { return ExprString(fn) }
+func (fn intersectionFn) String() string         { return ExprString(fn) }
+func (fn differenceFn) String() string           { return ExprString(fn) }
+func (fn distinctFn) String() string             { return ExprString(fn) }
+func (fn joinFn) String() string                 { return ExprString(fn) }
+func (fn rangeFn) String() string                { return ExprString(fn) }
+func (fn formatFn) String() string               { return ExprString(fn) }
+func (fn concatFn) String() string               { return ExprString(fn) }
+func (fn casefoldFn) String() string             { return ExprString(fn) }
+func (fn startsWithFn) String() string           { return ExprString(fn) }
+func (fn endsWithFn) String() string             { return ExprString(fn) }
+func (fn containsStrFn) String() string          { return ExprString(fn) }
+func (fn containsStrRegexFn) String() string     { return ExprString(fn) }
+func (fn regexEscapeFn) String() string          { return ExprString(fn) }
+func (fn findStrFn) String() string              { return ExprString(fn) }
+func (fn findStrRegexFn) String() string         { return ExprString(fn) }
+func (fn lengthFn) String() string               { return ExprString(fn) }
+func (fn lowercaseFn) String() string            { return ExprString(fn) }
+func (fn lTrimFn) String() string                { return ExprString(fn) }
+func (fn repeatFn) String() string               { return ExprString(fn) }
+func (fn replaceStrFn) String() string           { return ExprString(fn) }
+func (fn replaceStrRegexFn) String() string      { return ExprString(fn) }
+func (fn rTrimFn) String() string                { return ExprString(fn) }
+func (fn spaceFn) String() string                { return ExprString(fn) }
+func (fn subStringFn) String() string            { return ExprString(fn) }
+func (fn titleCaseFn) String() string            { return ExprString(fn) }
+func (fn trimFn) String() string                 { return ExprString(fn) }
+func (fn upperCaseFn) String() string            { return ExprString(fn) }
+func (fn toStringFn) String() string             { return ExprString(fn) }
+func (fn toNumberFn) String() string             { return ExprString(fn) }
+func (fn toDoubleFn) String() string             { return ExprString(fn) }
+func (fn toIntegerFn) String() string            { return ExprString(fn) }
+func (fn toObjectFn) String() string             { return ExprString(fn) }
+func (fn toArrayFn) String() string              { return ExprString(fn) }
+func (fn toTimeFn) String() string               { return ExprString(fn) }
+func (fn toDateFn) String() string               { return ExprString(fn) }
+func (fn isNumberFn) String() string             { return ExprString(fn) }
+func (fn isDoubleFn) String() string             { return ExprString(fn) }
+func (fn isIntegerFn) String() string            { return ExprString(fn) }
+func (fn isBooleanFn) String() string            { return ExprString(fn) }
+func (fn isNullFn) String() string               { return ExprString(fn) }
+func (fn isBytesFn) String() string              { return ExprString(fn) }
+func (fn isTimestampFn) String() string          { return ExprString(fn) }
+func (fn isDateFn) String() string               { return ExprString(fn) }
+func (fn isStringFn) String() string             { return ExprString(fn) }
+func (fn isArrayFn) String() string              { return ExprString(fn) }
+func (fn isObjectFn) String() string             { return ExprString(fn) }
+func (fn isRefFn) String() string                { return ExprString(fn) }
+func (fn isSetFn) String() string                { return ExprString(fn) }
+func (fn isDocFn) String() string                { return ExprString(fn) }
+func (fn isLambdaFn) String() string             { return ExprString(fn) }
+func (fn isCollectionFn) String() string         { return ExprString(fn) }
+func (fn isDatabaseFn) String() string           { return ExprString(fn) }
+func (fn isIndexFn) String() string              { return ExprString(fn) }
+func (fn isFunctionFn) String() string           { return ExprString(fn) }
+func (fn isKeyFn) String() string                { return ExprString(fn) }
+func (fn isTokenFn) String() string              { return ExprString(fn) }
+func (fn isCredentialsFn) String() string        { return ExprString(fn) }
+func (fn isRoleFn) String() string               { return ExprString(fn) }
+func (fn createFn) String() string               { return ExprString(fn) }
+func (fn createClassFn) String() string          { return ExprString(fn) }
+func (fn createCollectionFn) String() string     { return ExprString(fn) }
+func (fn createDatabaseFn) String() string       { return ExprString(fn) }
+func (fn createIndexFn) String() string          { return ExprString(fn) }
+func (fn createKeyFn) String() string            { return ExprString(fn) }
+func (fn createFunctionFn) String() string       { return ExprString(fn) }
+func (fn createRoleFn) String() string           { return ExprString(fn) }
+func (fn moveDatabaseFn) String() string         { return ExprString(fn) }
+func (fn updateFn) String() string               { return ExprString(fn) }
+func (fn replaceFn) String() string              { return ExprString(fn) }
+func (fn deleteFn) String() string               { return ExprString(fn) }
+func (fn insertFn) String() string               { return ExprString(fn) }
+func (fn removeFn) String() string               { return ExprString(fn) }
+func (fn createAccessProviderFn) String() string { return ExprString(fn) }
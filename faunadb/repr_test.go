@@ -0,0 +1,343 @@
+package faunadb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type reprCase struct {
+	expr Expr
+	fql  string
+}
+
+var reprCases = []reprCase{
+	// Values
+	{StringV("a \"quoted\" str"), `"a \"quoted\" str"`},
+	{LongV(42), `42`},
+	{LongV(-1), `-1`},
+	{DoubleV(3.14), `3.14`},
+	{DoubleV(10), `10.0`},
+	{BooleanV(true), `true`},
+	{BooleanV(false), `false`},
+	{NullV{}, `Null()`},
+	{ArrayV{LongV(1), StringV("a")}, `Arr{1, "a"}`},
+	{ObjectV{"b": LongV(2), "a": LongV(1)}, `Obj{"a": 1, "b": 2}`},
+	{Obj{"name": "John", "age": 24}, `Obj{"age": 24, "name": "John"}`},
+	{Arr{1, 2.5, "x", nil}, `Arr{1, 2.5, "x", Null()}`},
+	{Obj{"nested": Obj{"list": Arr{}}}, `Obj{"nested": Obj{"list": Arr{}}}`},
+
+	// Basic forms
+	{Abort("error"), `Abort("error")`},
+	{Do(Add(1, 2), Var("x")), `Do(Add(1, 2), Var("x"))`},
+	{Do(Var("x")), `Do(Var("x"))`},
+	{If(true, "yes", "no"), `If(true, "yes", "no")`},
+	{Lambda("x", Var("x")), `Lambda("x", Var("x"))`},
+	{Lambda(Arr{"x", "y"}, Add(Var("x"), Var("y"))), `Lambda(Arr{"x", "y"}, Add(Var("x"), Var("y")))`},
+	{At(1, Get(Var("ref"))), `At(1, Get(Var("ref")))`},
+	{Let().Bind("x", 1).Bind("y", 2).In(Var("x")), `Let().Bind("x", 1).Bind("y", 2).In(Var("x"))`},
+	{Call(Function("fn"), 1, "a"), `Call(Function("fn"), 1, "a")`},
+	{Call(Function("fn")), `Call(Function("fn"))`},
+	{Query(Lambda("x", Var("x"))), `Query(Lambda("x", Var("x")))`},
+	{Select(Arr{"data", "name"}, Var("doc")), `Select(Arr{"data", "name"}, Var("doc"))`},
+	{Select("name", Var("doc"), Default("none")), `Select("name", Var("doc"), Default("none"))`},
+	{SelectAll("name", Var("docs")), `SelectAll("name", Var("docs"))`},
+
+	// Collections
+	{Map(Arr{1, 2}, Lambda("x", Var("x"))), `Map(Arr{1, 2}, Lambda("x", Var("x")))`},
+	{Foreach(Arr{1, 2}, Lambda("x", Var("x"))), `Foreach(Arr{1, 2}, Lambda("x", Var("x")))`},
+	{Filter(Arr{1, 2}, Lambda("x", true)), `Filter(Arr{1, 2}, Lambda("x", true))`},
+	{Take(2, Arr{1, 2, 3}), `Take(2, Arr{1, 2, 3})`},
+	{Drop(2, Arr{1, 2, 3}), `Drop(2, Arr{1, 2, 3})`},
+	{Prepend(Arr{1}, Arr{2}), `Prepend(Arr{1}, Arr{2})`},
+	{Append(Arr{1}, Arr{2}), `Append(Arr{1}, Arr{2})`},
+	{IsEmpty(Arr{}), `IsEmpty(Arr{})`},
+	{IsNonEmpty(Arr{}), `IsNonEmpty(Arr{})`},
+	{Contains("a", Obj{}), `Contains("a", Obj{})`},
+	{ContainsPath("a", Obj{}), `ContainsPath("a", Obj{})`},
+	{ContainsValue(1, Arr{1}), `ContainsValue(1, Arr{1})`},
+	{ContainsField("a", Obj{}), `ContainsField("a", Obj{})`},
+	{Count(Arr{1}), `Count(Arr{1})`},
+	{Sum(Arr{1}), `Sum(Arr{1})`},
+	{Mean(Arr{1}), `Mean(Arr{1})`},
+	{Reverse(Arr{1}), `Reverse(Arr{1})`},
+
+	// Date and time
+	{Time("now"), `Time("now")`},
+	{TimeAdd(Now(), 1, TimeUnitDay), `TimeAdd(Now(), 1, "day")`},
+	{TimeSubtract(Now(), 1, TimeUnitHour), `TimeSubtract(Now(), 1, "hour")`},
+	{TimeDiff(Now(), Now(), TimeUnitSecond), `TimeDiff(Now(), Now(), "second")`},
+	{Date("1970-01-01"), `Date("1970-01-01")`},
+	{Epoch(0, TimeUnitSecond), `Epoch(0, "second")`},
+	{ToSeconds(Now()), `ToSeconds(Now())`},
+	{ToMillis(Now()), `ToMillis(Now())`},
+	{ToMicros(Now()), `ToMicros(Now())`},
+	{Year(Now()), `Year(Now())`},
+	{Month(Now()), `Month(Now())`},
+	{Hour(Now()), `Hour(Now())`},
+	{Minute(Now()), `Minute(Now())`},
+	{Second(Now()), `Second(Now())`},
+	{DayOfMonth(Now()), `DayOfMonth(Now())`},
+	{DayOfWeek(Now()), `DayOfWeek(Now())`},
+	{DayOfYear(Now()), `DayOfYear(Now())`},
+
+	// Logic
+	{Equals(1, 1), `Equals(1, 1)`},
+	{Any(Arr{true}), `Any(Arr{true})`},
+	{All(Arr{true}), `All(Arr{true})`},
+	{LT(1, 2), `LT(1, 2)`},
+	{LTE(1, 2), `LTE(1, 2)`},
+	{GT(1, 2), `GT(1, 2)`},
+	{GTE(1, 2), `GTE(1, 2)`},
+	{And(true, false), `And(true, false)`},
+	{Or(true, false), `Or(true, false)`},
+	{Not(true), `Not(true)`},
+
+	// Math
+	{Abs(-1), `Abs(-1)`},
+	{Acos(0), `Acos(0)`},
+	{Asin(0), `Asin(0)`},
+	{Atan(0), `Atan(0)`},
+	{Add(1, 2, 3), `Add(1, 2, 3)`},
+	{BitAnd(1, 2), `BitAnd(1, 2)`},
+	{BitNot(1), `BitNot(1)`},
+	{BitOr(1, 2), `BitOr(1, 2)`},
+	{BitXor(1, 2), `BitXor(1, 2)`},
+	{Ceil(1.5), `Ceil(1.5)`},
+	{Cos(0), `Cos(0)`},
+	{Cosh(0), `Cosh(0)`},
+	{Degrees(0), `Degrees(0)`},
+	{Divide(4, 2), `Divide(4, 2)`},
+	{Exp(1), `Exp(1)`},
+	{Floor(1.5), `Floor(1.5)`},
+	{Hypot(3, 4), `Hypot(3, 4)`},
+	{Ln(1), `Ln(1)`},
+	{Log(1), `Log(1)`},
+	{Max(1, 2), `Max(1, 2)`},
+	{Min(1, 2), `Min(1, 2)`},
+	{Modulo(5, 2), `Modulo(5, 2)`},
+	{Multiply(2, 3), `Multiply(2, 3)`},
+	{Pow(2, 8), `Pow(2, 8)`},
+	{Radians(0), `Radians(0)`},
+	{Round(1.234), `Round(1.234)`},
+	{Round(1.234, Precision(2)), `Round(1.234, Precision(2))`},
+	{Sign(-2), `Sign(-2)`},
+	{Sin(0), `Sin(0)`},
+	{Sinh(0), `Sinh(0)`},
+	{Sqrt(4), `Sqrt(4)`},
+	{Subtract(3, 2), `Subtract(3, 2)`},
+	{Tan(0), `Tan(0)`},
+	{Tanh(0), `Tanh(0)`},
+	{Trunc(1.234, Precision(1)), `Trunc(1.234, Precision(1))`},
+
+	// Read
+	{Get(Ref(Collection("users"), "1")), `Get(RefCollection(Collection("users"), "1"))`},
+	{Get(Var("ref"), TS(1)), `Get(Var("ref"), TS(1))`},
+	{KeyFromSecret("secret"), `KeyFromSecret("secret")`},
+	{Exists(Var("ref"), TS(1)), `Exists(Var("ref"), TS(1))`},
+	{Paginate(Match(Index("all_users"))), `Paginate(Match(Index("all_users")))`},
+	{
+		Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), Size(10), After(Var("cursor")), Sources(true), TS(1)),
+		`Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), After(Var("cursor")), Size(10), Sources(true), TS(1))`,
+	},
+	{Paginate(Var("set"), Before(Var("c")), EventsOpt(true)), `Paginate(Var("set"), Before(Var("c")), EventsOpt(true))`},
+	{Paginate(Var("set"), Cursor(Var("c"))), `Paginate(Var("set"), Cursor(Var("c")))`},
+
+	// Refs and schema
+	{Ref("collections/users"), `Ref("collections/users")`},
+	{RefCollection(Collection("users"), "1"), `RefCollection(Collection("users"), "1")`},
+	{Database("db"), `Database("db")`},
+	{ScopedDatabase("db", Database("parent")), `ScopedDatabase("db", Database("parent"))`},
+	{Index("idx"), `Index("idx")`},
+	{ScopedIndex("idx", Database("db")), `ScopedIndex("idx", Database("db"))`},
+	{Class("cls"), `Class("cls")`},
+	{ScopedClass("cls", Database("db")), `ScopedClass("cls", Database("db"))`},
+	{Collection("users"), `Collection("users")`},
+	{ScopedCollection("users", Database("db")), `ScopedCollection("users", Database("db"))`},
+	{Documents(Collection("users")), `Documents(Collection("users"))`},
+	{Function("fn"), `Function("fn")`},
+	{ScopedFunction("fn", Database("db")), `ScopedFunction("fn", Database("db"))`},
+	{Role("role"), `Role("role")`},
+	{ScopedRole("role", Database("db")), `ScopedRole("role", Database("db"))`},
+	{Classes(), `Classes()`},
+	{ScopedClasses(Database("db")), `ScopedClasses(Database("db"))`},
+	{Collections(), `Collections()`},
+	{ScopedCollections(Database("db")), `ScopedCollections(Database("db"))`},
+	{Indexes(), `Indexes()`},
+	{ScopedIndexes(Database("db")), `ScopedIndexes(Database("db"))`},
+	{Databases(), `Databases()`},
+	{ScopedDatabases(Database("db")), `ScopedDatabases(Database("db"))`},
+	{Functions(), `Functions()`},
+	{ScopedFunctions(Database("db")), `ScopedFunctions(Database("db"))`},
+	{Roles(), `Roles()`},
+	{ScopedRoles(Database("db")), `ScopedRoles(Database("db"))`},
+	{Keys(), `Keys()`},
+	{ScopedKeys(Database("db")), `ScopedKeys(Database("db"))`},
+	{Tokens(), `Tokens()`},
+	{ScopedTokens(Database("db")), `ScopedTokens(Database("db"))`},
+	{Credentials(), `Credentials()`},
+	{ScopedCredentials(Database("db")), `ScopedCredentials(Database("db"))`},
+	{NextID(), `NextID()`},
+	{NewId(), `NewId()`},
+	{AccessProvider("ap"), `AccessProvider("ap")`},
+	{ScopedAccessProvider("ap", Database("db")), `ScopedAccessProvider("ap", Database("db"))`},
+	{AccessProviders(), `AccessProviders()`},
+	{ScopedAccessProviders(Database("db")), `ScopedAccessProviders(Database("db"))`},
+	{CurrentIdentity(), `CurrentIdentity()`},
+	{CurrentToken(), `CurrentToken()`},
+	{HasCurrentIdentity(), `HasCurrentIdentity()`},
+	{HasCurrentToken(), `HasCurrentToken()`},
+
+	// Sets
+	{Singleton(Var("ref")), `Singleton(Var("ref"))`},
+	{Events(Var("ref")), `Events(Var("ref"))`},
+	{Match(Index("idx")), `Match(Index("idx"))`},
+	{MatchTerm(Index("idx"), Arr{"a", 1}), `MatchTerm(Index("idx"), Arr{"a", 1})`},
+	{Union(Var("a"), Var("b")), `Union(Var("a"), Var("b"))`},
+	{Merge(Obj{"a": 1}, Obj{"b": 2}), `Merge(Obj{"a": 1}, Obj{"b": 2})`},
+	{
+		Merge(Obj{"a": 1}, Obj{"a": 2}, ConflictResolver(Lambda(Arr{"k", "a", "b"}, Var("b")))),
+		`Merge(Obj{"a": 1}, Obj{"a": 2}, ConflictResolver(Lambda(Arr{"k", "a", "b"}, Var("b"))))`,
+	},
+	{Reduce(Lambda(Arr{"acc", "x"}, Var("x")), 0, Arr{1}), `Reduce(Lambda(Arr{"acc", "x"}, Var("x")), 0, Arr{1})`},
+	{Intersection(Var("a"), Var("b")), `Intersection(Var("a"), Var("b"))`},
+	{Difference(Var("a"), Var("b")), `Difference(Var("a"), Var("b"))`},
+	{Distinct(Var("a")), `Distinct(Var("a"))`},
+	{Join(Var("a"), Index("idx")), `Join(Var("a"), Index("idx"))`},
+	{Range(Var("a"), 1, 10), `Range(Var("a"), 1, 10)`},
+
+	// Strings
+	{Format("%s-%d", "a", 1), `Format("%s-%d", "a", 1)`},
+	{Concat(Arr{"a", "b"}), `Concat(Arr{"a", "b"})`},
+	{Concat(Arr{"a", "b"}, Separator("/")), `Concat(Arr{"a", "b"}, Separator("/"))`},
+	{Casefold("A"), `Casefold("A")`},
+	{Casefold("A", Normalizer(NormalizerNFKC)), `Casefold("A", Normalizer("NFKC"))`},
+	{StartsWith("abc", "a"), `StartsWith("abc", "a")`},
+	{EndsWith("abc", "c"), `EndsWith("abc", "c")`},
+	{ContainsStr("abc", "b"), `ContainsStr("abc", "b")`},
+	{ContainsStrRegex("abc", "b+"), `ContainsStrRegex("abc", "b+")`},
+	{RegexEscape("a.b"), `RegexEscape("a.b")`},
+	{FindStr("abc", "b"), `FindStr("abc", "b")`},
+	{FindStr("abc", "b", Start(1)), `FindStr("abc", "b", Start(1))`},
+	{FindStrRegex("abc", "b", Start(1), NumResults(2)), `FindStrRegex("abc", "b", Start(1), NumResults(2))`},
+	{Length("abc"), `Length("abc")`},
+	{LowerCase("ABC"), `LowerCase("ABC")`},
+	{LTrim(" a"), `LTrim(" a")`},
+	{Repeat("a"), `Repeat("a")`},
+	{Repeat("a", Number(3)), `Repeat("a", Number(3))`},
+	{ReplaceStr("abc", "b", "x"), `ReplaceStr("abc", "b", "x")`},
+	{ReplaceStrRegex("abc", "b", "x"), `ReplaceStrRegex("abc", "b", "x")`},
+	{ReplaceStrRegex("abb", "b", "x", OnlyFirst()), `ReplaceStrRegex("abb", "b", "x", OnlyFirst())`},
+	{RTrim("a "), `RTrim("a ")`},
+	{Space(2), `Space(2)`},
+	{SubString("abc", 1), `SubString("abc", 1)`},
+	{SubString("abc", 1, StrLength(1)), `SubString("abc", 1, StrLength(1))`},
+	{TitleCase("abc"), `TitleCase("abc")`},
+	{Trim(" a "), `Trim(" a ")`},
+	{UpperCase("abc"), `UpperCase("abc")`},
+
+	// Types
+	{ToString(1), `ToString(1)`},
+	{ToNumber("1"), `ToNumber("1")`},
+	{ToDouble(1), `ToDouble(1)`},
+	{ToInteger(1.5), `ToInteger(1.5)`},
+	{ToObject(Arr{}), `ToObject(Arr{})`},
+	{ToArray(Obj{}), `ToArray(Obj{})`},
+	{ToTime("2020-01-01T00:00:00Z"), `ToTime("2020-01-01T00:00:00Z")`},
+	{ToDate("2020-01-01"), `ToDate("2020-01-01")`},
+	{IsNumber(1), `IsNumber(1)`},
+	{IsDouble(1), `IsDouble(1)`},
+	{IsInteger(1), `IsInteger(1)`},
+	{IsBoolean(1), `IsBoolean(1)`},
+	{IsNull(1), `IsNull(1)`},
+	{IsBytes(1), `IsBytes(1)`},
+	{IsTimestamp(1), `IsTimestamp(1)`},
+	{IsDate(1), `IsDate(1)`},
+	{IsString(1), `IsString(1)`},
+	{IsArray(1), `IsArray(1)`},
+	{IsObject(1), `IsObject(1)`},
+	{IsRef(1), `IsRef(1)`},
+	{IsSet(1), `IsSet(1)`},
+	{IsDoc(1), `IsDoc(1)`},
+	{IsLambda(1), `IsLambda(1)`},
+	{IsCollection(1), `IsCollection(1)`},
+	{IsDatabase(1), `IsDatabase(1)`},
+	{IsIndex(1), `IsIndex(1)`},
+	{IsFunction(1), `IsFunction(1)`},
+	{IsKey(1), `IsKey(1)`},
+	{IsToken(1), `IsToken(1)`},
+	{IsCredentials(1), `IsCredentials(1)`},
+	{IsRole(1), `IsRole(1)`},
+
+	// Write
+	{Create(Collection("users"), Obj{"data": Obj{"name": "John"}}), `Create(Collection("users"), Obj{"data": Obj{"name": "John"}})`},
+	{CreateClass(Obj{"name": "c"}), `CreateClass(Obj{"name": "c"})`},
+	{CreateCollection(Obj{"name": "c"}), `CreateCollection(Obj{"name": "c"})`},
+	{CreateDatabase(Obj{"name": "d"}), `CreateDatabase(Obj{"name": "d"})`},
+	{CreateIndex(Obj{"name": "i"}), `CreateIndex(Obj{"name": "i"})`},
+	{CreateKey(Obj{"role": "admin"}), `CreateKey(Obj{"role": "admin"})`},
+	{CreateFunction(Obj{"name": "f"}), `CreateFunction(Obj{"name": "f"})`},
+	{CreateRole(Obj{"name": "r"}), `CreateRole(Obj{"name": "r"})`},
+	{CreateAccessProvider(Obj{"name": "ap"}), `CreateAccessProvider(Obj{"name": "ap"})`},
+	{MoveDatabase(Database("a"), Database("b")), `MoveDatabase(Database("a"), Database("b"))`},
+	{Update(Var("ref"), Obj{"data": Obj{}}), `Update(Var("ref"), Obj{"data": Obj{}})`},
+	{Replace(Var("ref"), Obj{"data": Obj{}}), `Replace(Var("ref"), Obj{"data": Obj{}})`},
+	{Delete(Var("ref")), `Delete(Var("ref"))`},
+	{Insert(Var("ref"), 1, ActionCreate, Obj{}), `Insert(Var("ref"), 1, "create", Obj{})`},
+	{Remove(Var("ref"), 1, ActionDelete), `Remove(Var("ref"), 1, "delete")`},
+
+	// Auth
+	{Login(Var("ref"), Obj{"password": "p"}), `Login(Var("ref"), Obj{"password": "p"})`},
+	{Logout(true), `Logout(true)`},
+	{Identify(Var("ref"), "p"), `Identify(Var("ref"), "p")`},
+	{Identity(), `Identity()`},
+	{HasIdentity(), `HasIdentity()`},
+}
+
+func TestExprString(t *testing.T) {
+	for _, c := range reprCases {
+		require.Equal(t, c.fql, ExprString(c.expr))
+	}
+}
+
+func TestExprStringer(t *testing.T) {
+	expr := Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), Size(10))
+
+	require.Equal(t, `Paginate(MatchTerm(Index("users_by_email"), "a@b.c"), Size(10))`, fmt.Sprint(expr))
+	require.Equal(t, `Obj{"a": Arr{1, "b"}}`, fmt.Sprintf("%s", ObjectV{"a": ArrayV{LongV(1), StringV("b")}}))
+	require.Equal(t, `Obj{"a": Var("x")}`, fmt.Sprint(Obj{"a": Var("x")}))
+}
+
+func TestRefValueString(t *testing.T) {
+	db := RefV{"db", NativeDatabases(), NativeDatabases(), nil}
+	users := RefV{"users", NativeCollections(), NativeCollections(), nil}
+	scopedUsers := RefV{"users", NativeCollections(), NativeCollections(), &db}
+
+	require.Equal(t, `Collections()`, ExprString(*NativeCollections()))
+	require.Equal(t, `Collection("users")`, ExprString(users))
+	require.Equal(t, `RefCollection(Collection("users"), "1")`, ExprString(RefV{"1", &users, &users, nil}))
+	require.Equal(t, `ScopedCollection("users", Database("db"))`, ExprString(scopedUsers))
+	require.Equal(t, `RefCollection(ScopedCollection("users", Database("db")), "1")`, ExprString(RefV{"1", &scopedUsers, &scopedUsers, nil}))
+	require.Equal(t, `RefCollection(Keys(), "1")`, ExprString(RefV{"1", NativeKeys(), NativeKeys(), nil}))
+	require.Equal(t, `RefCollection(ScopedKeys(Database("db")), "1")`, ExprString(RefV{"1", NativeKeys(), NativeKeys(), &db}))
+	require.Equal(t, `RefCollection(Tokens(), "1")`, ExprString(RefV{"1", NativeTokens(), NativeTokens(), nil}))
+	require.Equal(t, `RefCollection(Credentials(), "1")`, ExprString(RefV{"1", NativeCredentials(), NativeCredentials(), nil}))
+	require.Equal(t, `AccessProviders()`, ExprString(RefV{ID: "access_providers"}))
+	require.Equal(t, `AccessProvider("auth0")`, ExprString(RefV{"auth0", &RefV{ID: "access_providers"}, &RefV{ID: "access_providers"}, nil}))
+	require.Equal(t, `Index("idx")`, ExprString(RefV{"idx", NativeIndexes(), NativeIndexes(), nil}))
+}
+
+func TestSpecialValuesString(t *testing.T) {
+	date := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	require.Equal(t, `Date("2020-01-02")`, ExprString(DateV(date)))
+	require.Equal(t, `Time("2020-01-02T03:04:05Z")`, ExprString(TimeV(date)))
+	require.Equal(t, `Time("2020-01-02T03:04:05Z")`, ExprString(TimeV(date.In(time.FixedZone("UTC-5", -5*60*60)))))
+	require.Equal(t, `BytesV{0x01, 0x02, 0xff}`, ExprString(BytesV{1, 2, 255}))
+	require.Equal(t, `Match(Index("idx"))`, ExprString(SetRefV{map[string]Value{"match": RefV{"idx", NativeIndexes(), NativeIndexes(), nil}}}))
+	require.Equal(t, `MatchTerm(Index("idx"), "a")`, ExprString(SetRefV{map[string]Value{"match": RefV{"idx", NativeIndexes(), NativeIndexes(), nil}, "terms": StringV("a")}}))
+	require.Equal(t, `Query(Lambda("x", Add(Var("x"), 1)))`, ExprString(QueryV{[]byte(`{"api_version": "4", "lambda": "x", "expr": {"add": [{"var": "x"}, 1]}}`)}))
+	require.Equal(t, `1e+21`, ExprString(DoubleV(1e21)))
+}
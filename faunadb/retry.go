@@ -0,0 +1,120 @@
+package faunadb
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff describes an exponential backoff with jitter used between consecutive attempts.
+type Backoff struct {
+	Initial    time.Duration // Delay before the first retry
+	Max        time.Duration // Upper bound for the delay between attempts
+	Multiplier float64       // Factor applied to the delay after each attempt
+	Jitter     float64       // Fraction of the delay, between 0 and 1, that is randomized
+}
+
+// DefaultBackoff returns a Backoff starting at 100ms, doubling up to 5s with 50% jitter.
+func DefaultBackoff() Backoff {
+	return Backoff{
+		Initial:    100 * time.Millisecond,
+		Max:        5 * time.Second,
+		Multiplier: 2,
+		Jitter:     0.5,
+	}
+}
+
+// Delay returns the time to wait before the given retry. Retries are numbered from 1.
+func (b Backoff) Delay(retry int) time.Duration {
+	if retry < 1 || b.Initial <= 0 {
+		return 0
+	}
+
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(b.Initial) * math.Pow(multiplier, float64(retry-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		delay = float64(b.Max)
+	}
+
+	if jitter := math.Min(math.Max(b.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// RetryOptions describes which failed queries are retried by a FaunaClient and how.
+type RetryOptions struct {
+	MaxAttempts       int      // Maximum number of attempts, including the first one
+	Backoff           Backoff  // Delay between attempts
+	RetryableStatuses []int    // HTTP status codes that are retried
+	RetryableCodes    []string // Query error codes that are retried, such as "contended transaction"
+}
+
+// DefaultRetryOptions returns RetryOptions with up to 3 attempts on contended transactions,
+// throttled requests and unavailable responses.
+func DefaultRetryOptions() RetryOptions {
+	return RetryOptions{
+		MaxAttempts:       3,
+		Backoff:           DefaultBackoff(),
+		RetryableStatuses: []int{429, 503},
+		RetryableCodes:    []string{"contended transaction"},
+	}
+}
+
+// RetryPolicy configures a FaunaClient to retry failed queries according to the given options.
+// All attempts of a query share the query timeout configured with QueryTimeoutMS, and retries
+// that would exceed it are not performed.
+func RetryPolicy(opts RetryOptions) ClientConfig {
+	return func(cli *FaunaClient) { cli.retry = opts }
+}
+
+func (opts RetryOptions) shouldRetry(err error, attempts int) bool {
+	if attempts >= opts.MaxAttempts {
+		return false
+	}
+
+	faunaErr, ok := err.(FaunaError)
+	if !ok {
+		return false
+	}
+
+	for _, status := range opts.RetryableStatuses {
+		if faunaErr.Status() == status {
+			return true
+		}
+	}
+
+	for _, queryErr := range faunaErr.Errors() {
+		for _, code := range opts.RetryableCodes {
+			if queryErr.Code == code {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// waitForRetry sleeps for the backoff delay of the given retry. It returns false without waiting
+// if the context would expire before the delay elapses.
+func waitForRetry(ctx context.Context, delay time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
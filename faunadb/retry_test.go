@@ -0,0 +1,118 @@
+package faunadb
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var contendedTransactionBody = `{"errors": [{"code": "contended transaction", "description": "Transaction was aborted due to detection of concurrent modification."}]}`
+
+func failingHandler(failures int32, status int, body string) (http.HandlerFunc, *int32) {
+	var calls int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+			return
+		}
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}, &calls
+}
+
+func fastRetries(attempts int) RetryOptions {
+	opts := DefaultRetryOptions()
+	opts.MaxAttempts = attempts
+	opts.Backoff = Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, Multiplier: 2}
+	return opts
+}
+
+func TestRetryContendedTransaction(t *testing.T) {
+	var attempts int
+
+	handler, calls := failingHandler(2, 409, contendedTransactionBody)
+	client, closeServer := mockedClient(handler,
+		RetryPolicy(fastRetries(3)),
+		Observer(func(result *QueryResult) { attempts = result.Attempts }),
+	)
+	defer closeServer()
+
+	value, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, LongV(42), value)
+	require.Equal(t, int32(3), atomic.LoadInt32(calls))
+	require.Equal(t, 3, attempts)
+}
+
+func TestRetryTooManyRequests(t *testing.T) {
+	handler, calls := failingHandler(1, 429, `{"errors": []}`)
+	client, closeServer := mockedClient(handler, RetryPolicy(fastRetries(3)))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	handler, calls := failingHandler(5, 503, `{"errors": []}`)
+	client, closeServer := mockedClient(handler, RetryPolicy(fastRetries(3)))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Equal(t, Unavailable{errorResponseWith(503, noErrors)}, err)
+	require.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestDoNotRetryNonRetryableErrors(t *testing.T) {
+	handler, calls := failingHandler(1, 400, `{"errors": []}`)
+	client, closeServer := mockedClient(handler, RetryPolicy(fastRetries(3)))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Equal(t, BadRequest{errorResponseWith(400, noErrors)}, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestDoNotRetryByDefault(t *testing.T) {
+	handler, calls := failingHandler(1, 503, `{"errors": []}`)
+	client, closeServer := mockedClient(handler)
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Error(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestRetryRespectsQueryTimeout(t *testing.T) {
+	opts := fastRetries(10)
+	opts.Backoff = Backoff{Initial: time.Second}
+
+	handler, calls := failingHandler(5, 503, `{"errors": []}`)
+	client, closeServer := mockedClient(handler, RetryPolicy(opts), QueryTimeoutMS(100))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.Equal(t, Unavailable{errorResponseWith(503, noErrors)}, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Initial: 100 * time.Millisecond, Max: time.Second, Multiplier: 2}
+
+	require.Equal(t, time.Duration(0), backoff.Delay(0))
+	require.Equal(t, 100*time.Millisecond, backoff.Delay(1))
+	require.Equal(t, 200*time.Millisecond, backoff.Delay(2))
+	require.Equal(t, 400*time.Millisecond, backoff.Delay(3))
+	require.Equal(t, time.Second, backoff.Delay(10))
+
+	backoff.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		delay := backoff.Delay(2)
+		require.True(t, delay > 100*time.Millisecond && delay <= 200*time.Millisecond)
+	}
+}
@@ -232,7 +232,89 @@ func TestSerializeStructWithEmbeddedStructs(t *testing.T) {
 
 	assertJSON(t,
 		Obj{"data": Data{42, Embedded{"a string"}}},
-		`{"object":{"data":{"object":{"Embedded":{"object":{"Str":"a string"}},"Int":42}}}}`,
+		`{"object":{"data":{"object":{"Str":"a string","Int":42}}}}`,
+	)
+}
+
+func TestSerializeStructWithEmbeddedPointers(t *testing.T) {
+	type Base struct {
+		ID string `fauna:"id"`
+	}
+
+	type Data struct {
+		*Base
+		Int int
+	}
+
+	assertJSON(t,
+		Obj{"data": Data{&Base{"1"}, 42}},
+		`{"object":{"data":{"object":{"id":"1","Int":42}}}}`,
+	)
+
+	assertJSON(t,
+		Obj{"data": Data{nil, 42}},
+		`{"object":{"data":{"object":{"Int":42}}}}`,
+	)
+}
+
+func TestSerializeStructWithEmbeddedConflicts(t *testing.T) {
+	type A struct {
+		Name  string
+		Tag   string
+		Same  string
+		Inner string
+	}
+
+	type B struct {
+		Name string `fauna:"Tag"`
+		Same string
+	}
+
+	type Nested struct {
+		A
+	}
+
+	type Data struct {
+		A
+		B
+		Nested
+		Inner string
+		Named A `fauna:"named"`
+	}
+
+	data := Data{
+		A:      A{"a name", "a tag", "a same", "a inner"},
+		B:      B{"b tag", "b same"},
+		Nested: Nested{A{"nested", "nested", "nested", "nested"}},
+		Inner:  "inner",
+		Named:  A{Name: "named"},
+	}
+
+	assertJSON(t,
+		Obj{"data": data},
+		`{"object":{"data":{"object":{
+			"Name":"a name",
+			"Tag":"b tag",
+			"Inner":"inner",
+			"named":{"object":{"Name":"named","Tag":"","Same":"","Inner":""}}
+		}}}}`,
+	)
+}
+
+func TestSerializeStructWithInlineFields(t *testing.T) {
+	type Audit struct {
+		CreatedBy string `fauna:"created_by"`
+	}
+
+	type Data struct {
+		Name  string            `fauna:"name"`
+		Audit Audit             `fauna:",inline"`
+		Extra map[string]string `fauna:",inline"`
+	}
+
+	assertJSON(t,
+		Obj{"data": Data{"Jhon", Audit{"admin"}, map[string]string{"name": "ignored", "color": "blue"}}},
+		`{"object":{"data":{"object":{"name":"Jhon","created_by":"admin","color":"blue"}}}}`,
 	)
 }
 
@@ -1,5 +1,10 @@
 package faunadb
 
+import (
+	"errors"
+	"time"
+)
+
 // StreamField represents a stream field
 type StreamField string
 
@@ -12,7 +17,11 @@ const (
 )
 
 type streamConfig struct {
-	Fields []StreamField
+	Fields      []StreamField
+	Reconnect   *ReconnectOptions
+	Overflow    StreamOverflowPolicy
+	ReplayFrom  *int64
+	IdleTimeout time.Duration
 }
 
 // StreamConfig describes optional parameters for a stream subscription
@@ -39,3 +48,88 @@ func Fields(fields ...StreamField) StreamConfig {
 		sub.config.Fields = []StreamField(fields)
 	}
 }
+
+// ReconnectOptions describes how a stream subscription reconnects after its connection is lost.
+type ReconnectOptions struct {
+	MaxAttempts int     // Maximum number of consecutive reconnection attempts, or 0 for no limit
+	Backoff     Backoff // Delay between reconnection attempts
+}
+
+// DefaultReconnectOptions returns ReconnectOptions with no attempts limit and the default backoff.
+func DefaultReconnectOptions() ReconnectOptions {
+	return ReconnectOptions{Backoff: DefaultBackoff()}
+}
+
+// Reconnect is optional stream parameter that re-issues the stream request when its connection is lost.
+// The new connection resumes from the transaction time of the last delivered event: a ReconnectEvent is emitted
+// instead of the new StartEvent, followed by the events committed while disconnected, replayed from the history
+// of the stream query as with ReplayFrom, and events already delivered are skipped. While reconnecting, the subscription
+// status is StreamConnIdle. Streams closed by an ErrorEvent are not resumed.
+func Reconnect(opts ReconnectOptions) StreamConfig {
+	return func(sub *StreamSubscription) {
+		sub.config.Reconnect = &opts
+	}
+}
+
+// StreamOverflowPolicy describes what a stream subscription does when its events buffer is full
+type StreamOverflowPolicy int
+
+const (
+	// StreamOverflowBlock waits for the consumer to receive events, pausing the stream connection
+	StreamOverflowBlock StreamOverflowPolicy = iota
+	// StreamOverflowDropOldest discards the oldest buffered event to make room for the new one
+	StreamOverflowDropOldest
+	// StreamOverflowClose closes the subscription, emitting an ErrorEvent with ErrStreamOverflow
+	// after the buffered events
+	StreamOverflowClose
+)
+
+// ErrStreamOverflow is the error of the ErrorEvent emitted when a subscription with the StreamOverflowClose
+// policy can not buffer an event.
+var ErrStreamOverflow = errors.New("stream events buffer overflow")
+
+// BufferSize is optional stream parameter that sets the number of events buffered for a slow consumer.
+// What happens when the buffer is full is configured with Overflow.
+func BufferSize(size int) StreamConfig {
+	return func(sub *StreamSubscription) {
+		if size < 0 {
+			size = 0
+		}
+		sub.events = make(chan StreamEvent, size)
+	}
+}
+
+// Overflow is optional stream parameter that sets the policy applied when the events buffer is full.
+// Defaults to StreamOverflowBlock. Discarded events are counted by the subscription DroppedEvents method.
+func Overflow(policy StreamOverflowPolicy) StreamConfig {
+	return func(sub *StreamSubscription) {
+		sub.config.Overflow = policy
+	}
+}
+
+// ReplayFrom is optional stream parameter that replays the history of the stream query after the given
+// transaction time. Once the stream starts, events between ts, exclusive, and the StartEvent are read
+// with Paginate(Events(query), After(ts)) and delivered right after the StartEvent, before any live event.
+// Replayed events have transaction times lower than the StartEvent one. Live events already replayed are
+// skipped, so consumers receive each event once. Replayed events hold the fields selected with Fields; the
+// diff and prev fields are computed from the previous version of each document, read with Get at that time.
+func ReplayFrom(ts int64) StreamConfig {
+	return func(sub *StreamSubscription) {
+		sub.config.ReplayFrom = &ts
+	}
+}
+
+// ErrStreamIdle is the error of the ErrorEvent emitted when a subscription with an IdleTimeout receives
+// no data from its stream connection within the timeout.
+var ErrStreamIdle = errors.New("stream connection idle timeout")
+
+// IdleTimeout is optional stream parameter that detects stale stream connections, such as half-open TCP
+// connections. If no data, including the empty frames periodically sent by Fauna, is received for the
+// given duration, an ErrorEvent with ErrStreamIdle is emitted and the connection is dropped. Subscriptions
+// configured to Reconnect reconnect, others are closed with StreamConnError status. Time spent waiting for
+// the consumer to receive events does not count as idle.
+func IdleTimeout(timeout time.Duration) StreamConfig {
+	return func(sub *StreamSubscription) {
+		sub.config.IdleTimeout = timeout
+	}
+}
@@ -23,6 +23,9 @@ const (
 
 	// SetT is the stream set event type
 	SetEventT StreamEventType = "set"
+
+	// ReconnectEventT is the stream reconnect event type
+	ReconnectEventT StreamEventType = "reconnect"
 )
 
 // StreamEvent represents a stream event with a `type` and `txn`
@@ -87,6 +90,31 @@ func (event VersionEvent) Type() StreamEventType {
 	return VersionEventT
 }
 
+// Action returns the action that modified the document: create, update or delete
+func (event VersionEvent) Action() (string, error) {
+	return eventAction(event.event)
+}
+
+// Document returns the document as of the event
+func (event VersionEvent) Document() (Value, error) {
+	return eventField(event.event, DocumentField)
+}
+
+// Diff returns the fields of the document that changed
+func (event VersionEvent) Diff() (Value, error) {
+	return eventField(event.event, DiffField)
+}
+
+// Prev returns the document before the event
+func (event VersionEvent) Prev() (Value, error) {
+	return eventField(event.event, PrevField)
+}
+
+// DecodeDocument decodes the document as of the event into a native Go type, as Value.Get does
+func (event VersionEvent) DecodeDocument(i interface{}) error {
+	return decodeEventField(event.event, DocumentField, i)
+}
+
 // VersionEvent represents a version event that occurs upon any
 // modifications to the current state of the subscribed document.
 type SetEvent struct {
@@ -114,6 +142,33 @@ func (event SetEvent) Type() StreamEventType {
 	return SetEventT
 }
 
+// Action returns the action that modified the set: add or remove
+func (event SetEvent) Action() (string, error) {
+	return eventAction(event.event)
+}
+
+// Document returns the document added to or removed from the set
+func (event SetEvent) Document() (Value, error) {
+	return eventField(event.event, DocumentField)
+}
+
+// Index returns the index terms and values of the document added to or removed from the set
+func (event SetEvent) Index() (index SetEventIndex, err error) {
+	err = decodeEventField(event.event, IndexField, &index)
+	return
+}
+
+// DecodeDocument decodes the document added to or removed from the set into a native Go type, as Value.Get does
+func (event SetEvent) DecodeDocument(i interface{}) error {
+	return decodeEventField(event.event, DocumentField, i)
+}
+
+// SetEventIndex holds the index terms and values of the document of a set event
+type SetEventIndex struct {
+	Terms  ArrayV `fauna:"terms"`
+	Values ArrayV `fauna:"values"`
+}
+
 // HistoryRewriteEvent represents a history rewrite event which occurs upon any modifications
 // to the history of the subscribed document.
 type HistoryRewriteEvent struct {
@@ -168,6 +223,74 @@ func (event ErrorEvent) String() string {
 	return fmt.Sprintf("ErrorEvent{error=%s}", event.err)
 }
 
+// ReconnectEvent is emitted by subscriptions configured with Reconnect, in place of the StartEvent,
+// when the stream resumes after its connection was lost.
+type ReconnectEvent struct {
+	StreamEvent
+	txn      int64
+	attempts int
+	cause    error
+}
+
+// Type returns the stream event type
+func (event ReconnectEvent) Type() StreamEventType {
+	return ReconnectEventT
+}
+
+// Txn returns the start timestamp of the resumed stream
+func (event ReconnectEvent) Txn() int64 {
+	return event.txn
+}
+
+// Attempts returns the number of attempts needed to reconnect
+func (event ReconnectEvent) Attempts() int {
+	return event.attempts
+}
+
+// Cause returns the error that ended the previous connection
+func (event ReconnectEvent) Cause() error {
+	return event.cause
+}
+
+func (event ReconnectEvent) String() string {
+	return fmt.Sprintf("ReconnectEvent{txn=%d, attempts=%d, cause=%s}", event.Txn(), event.Attempts(), event.Cause())
+}
+
+func eventField(event Value, field StreamField) (Value, error) {
+	obj, ok := event.(ObjectV)
+	if !ok {
+		return nil, DecodeError{err: fmt.Errorf("Expected stream event to be an object but was a %T", event)}
+	}
+
+	value, found := obj[string(field)]
+	if !found {
+		return nil, DecodeError{
+			path: pathFromKeys(string(field)),
+			err:  fmt.Errorf("Field not present in the stream event. Make sure it is selected with the Fields stream parameter"),
+		}
+	}
+
+	return value, nil
+}
+
+func decodeEventField(event Value, field StreamField, i interface{}) error {
+	value, err := eventField(event, field)
+	if err != nil {
+		return err
+	}
+
+	if err = value.Get(i); err != nil {
+		return DecodeError{path: pathFromKeys(string(field)), err: err}
+	}
+
+	return nil
+}
+
+func eventAction(event Value) (action string, err error) {
+	err = decodeEventField(event, ActionField, &action)
+	return
+}
+
 func unMarshalStreamEvent(data Obj) (evt StreamEvent, err error) {
 	if tpe, ok := data["type"]; ok {
 		switch StreamEventType(tpe.(StringV)) {
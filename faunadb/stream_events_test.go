@@ -0,0 +1,112 @@
+package faunadb
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func parseStreamEvent(t *testing.T, raw string) StreamEvent {
+	value, err := parseJSON(strings.NewReader(raw))
+	require.NoError(t, err)
+
+	var obj Obj
+	require.NoError(t, value.Get(&obj))
+
+	event, err := unMarshalStreamEvent(obj)
+	require.NoError(t, err)
+	return event
+}
+
+func TestVersionEventAccessors(t *testing.T) {
+	event := parseStreamEvent(t, `{"type": "version", "txn": 2, "event": {
+		"action": "update",
+		"document": {"ref": {"@ref": {"id": "1"}}, "ts": 2, "data": {"name": "Jane", "age": 30}},
+		"diff": {"data": {"name": "Jane"}},
+		"prev": {"ref": {"@ref": {"id": "1"}}, "ts": 1, "data": {"name": "John", "age": 30}}
+	}}`).(VersionEvent)
+
+	action, err := event.Action()
+	require.NoError(t, err)
+	require.Equal(t, "update", action)
+
+	document, err := event.Document()
+	require.NoError(t, err)
+	ts, _ := document.At(ObjKey("ts")).GetValue()
+	require.Equal(t, LongV(2), ts)
+
+	diff, err := event.Diff()
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{"data": ObjectV{"name": StringV("Jane")}}, diff)
+
+	prev, err := event.Prev()
+	require.NoError(t, err)
+	ts, _ = prev.At(ObjKey("ts")).GetValue()
+	require.Equal(t, LongV(1), ts)
+
+	var user struct {
+		Ts   int64 `fauna:"ts"`
+		Data struct {
+			Name string `fauna:"name"`
+			Age  int    `fauna:"age"`
+		} `fauna:"data"`
+	}
+
+	require.NoError(t, event.DecodeDocument(&user))
+	require.Equal(t, int64(2), user.Ts)
+	require.Equal(t, "Jane", user.Data.Name)
+	require.Equal(t, 30, user.Data.Age)
+}
+
+func TestVersionEventMissingFields(t *testing.T) {
+	event := parseStreamEvent(t, `{"type": "version", "txn": 2, "event": {"action": "delete", "document": {"data": {"age": "old"}}}}`).(VersionEvent)
+
+	_, err := event.Prev()
+	require.EqualError(t, err, "Error while decoding fauna value at: prev. Field not present in the stream event. Make sure it is selected with the Fields stream parameter")
+	require.IsType(t, DecodeError{}, err)
+
+	_, err = event.Diff()
+	require.IsType(t, DecodeError{}, err)
+
+	var user struct {
+		Data struct {
+			Age int `fauna:"age"`
+		} `fauna:"data"`
+	}
+
+	err = event.DecodeDocument(&user)
+	require.IsType(t, DecodeError{}, err)
+	require.EqualError(t, err, `Error while decoding fauna value at: document / Data / Age. Can not assign value of type "faunadb.StringV" to a value of type "int"`)
+}
+
+func TestSetEventAccessors(t *testing.T) {
+	event := parseStreamEvent(t, `{"type": "set", "txn": 3, "event": {
+		"action": "add",
+		"document": {"ref": {"@ref": {"id": "1"}}, "ts": 3},
+		"index": {"terms": ["a@b.c"], "values": [10, "Jane"]}
+	}}`).(SetEvent)
+
+	action, err := event.Action()
+	require.NoError(t, err)
+	require.Equal(t, "add", action)
+
+	index, err := event.Index()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{StringV("a@b.c")}, index.Terms)
+	require.Equal(t, ArrayV{LongV(10), StringV("Jane")}, index.Values)
+
+	var document struct {
+		Ref RefV  `fauna:"ref"`
+		Ts  int64 `fauna:"ts"`
+	}
+
+	require.NoError(t, event.DecodeDocument(&document))
+	require.Equal(t, RefV{ID: "1"}, document.Ref)
+	require.Equal(t, int64(3), document.Ts)
+
+	missing := parseStreamEvent(t, `{"type": "set", "txn": 3, "event": {"action": "remove"}}`).(SetEvent)
+
+	_, err = missing.Index()
+	require.EqualError(t, err, "Error while decoding fauna value at: index. Field not present in the stream event. Make sure it is selected with the Fields stream parameter")
+}
@@ -0,0 +1,267 @@
+package faunadb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// StreamGroupEvent is a stream event received by a StreamGroup, tagged with the query of its subscription.
+type StreamGroupEvent struct {
+	Query Expr
+	Event StreamEvent
+}
+
+// StreamGroupStatus counts the subscriptions of a StreamGroup by connection status.
+type StreamGroupStatus struct {
+	Active int
+	Idle   int
+	Closed int
+	Error  int
+}
+
+// StreamGroup multiplexes many stream subscriptions into a single events channel. Subscriptions can be added
+// and removed at runtime, and are identified by the FQL representation of their query, as returned by ExprString.
+// Each subscription still reads its stream connection in its own goroutine, while the group merges their events
+// in a single loop. New groups must be constructed via the FaunaClient StreamGroup method.
+type StreamGroup struct {
+	mu      sync.Mutex
+	client  *FaunaClient
+	config  []StreamConfig
+	entries map[string]*streamGroupEntry
+	events  chan StreamGroupEvent
+	changed chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	closed  bool
+}
+
+type streamGroupEntry struct {
+	sub     StreamSubscription
+	cancel  context.CancelFunc
+	started bool
+}
+
+// status returns the status of the entry subscription, idle while being started. Must hold the group mutex.
+func (entry *streamGroupEntry) status() StreamConnectionStatus {
+	if !entry.started {
+		return StreamConnIdle
+	}
+	return entry.sub.Status()
+}
+
+// StreamGroup creates a group of stream subscriptions. The given stream parameters, such as Reconnect, are
+// shared by all subscriptions added to the group. The group lives until its Close method is called.
+func (client *FaunaClient) StreamGroup(config ...StreamConfig) *StreamGroup {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	group := &StreamGroup{
+		client:  client,
+		config:  config,
+		entries: make(map[string]*streamGroupEntry),
+		events:  make(chan StreamGroupEvent),
+		changed: make(chan struct{}, 1),
+		ctx:     ctx,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go group.run()
+	return group
+}
+
+// Add starts a stream subscription to the given query and forwards its events to the group. Stream parameters
+// given here are applied after the ones shared by the group. It fails if the query is already in the group and its
+// subscription is still running or being started. The group is not locked while the subscription connects.
+func (group *StreamGroup) Add(query Expr, config ...StreamConfig) error {
+	key := ExprString(query)
+
+	group.mu.Lock()
+	if group.closed {
+		group.mu.Unlock()
+		return errors.New("stream group closed")
+	}
+
+	if entry, ok := group.entries[key]; ok {
+		if status := entry.status(); status == StreamConnActive || status == StreamConnIdle {
+			group.mu.Unlock()
+			return fmt.Errorf("stream group already subscribed to %s", key)
+		}
+		entry.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(group.ctx)
+	entry := &streamGroupEntry{
+		sub:    group.client.Stream(query, append(append([]StreamConfig{}, group.config...), config...)...),
+		cancel: cancel,
+	}
+
+	// Reserve the query while connecting, without blocking the group
+	group.entries[key] = entry
+	group.mu.Unlock()
+
+	err := entry.sub.StartContext(ctx)
+
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	if group.entries[key] != entry {
+		// Removed while connecting
+		cancel()
+		if err == nil {
+			err = fmt.Errorf("stream group subscription to %s removed while starting", key)
+		}
+		return err
+	}
+
+	if group.closed && err == nil {
+		err = errors.New("stream group closed")
+	}
+
+	if err != nil {
+		cancel()
+		delete(group.entries, key)
+		return err
+	}
+
+	entry.started = true
+	group.notify()
+
+	return nil
+}
+
+// Remove closes the subscription to the given query and removes it from the group. It returns false if the
+// query is not in the group.
+func (group *StreamGroup) Remove(query Expr) bool {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	key := ExprString(query)
+	entry, ok := group.entries[key]
+	if ok {
+		entry.cancel()
+		delete(group.entries, key)
+		group.notify()
+	}
+
+	return ok
+}
+
+// Len returns the number of subscriptions in the group.
+func (group *StreamGroup) Len() int {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+	return len(group.entries)
+}
+
+// Status returns the number of subscriptions of the group by connection status. Subscriptions being started
+// by Add are idle.
+func (group *StreamGroup) Status() (status StreamGroupStatus) {
+	group.mu.Lock()
+	defer group.mu.Unlock()
+
+	for _, entry := range group.entries {
+		switch entry.status() {
+		case StreamConnActive:
+			status.Active++
+		case StreamConnIdle:
+			status.Idle++
+		case StreamConnClosed:
+			status.Closed++
+		case StreamConnError:
+			status.Error++
+		}
+	}
+
+	return
+}
+
+// StreamEvents returns the channel of events received by all subscriptions of the group. The channel is
+// closed when the group is closed.
+func (group *StreamGroup) StreamEvents() <-chan StreamGroupEvent {
+	return group.events
+}
+
+// Close closes all subscriptions of the group and its events channel.
+func (group *StreamGroup) Close() {
+	group.mu.Lock()
+	if group.closed {
+		group.mu.Unlock()
+		return
+	}
+	group.closed = true
+	group.cancel()
+	group.mu.Unlock()
+
+	<-group.done
+	close(group.events)
+}
+
+// notify wakes up the group loop after its subscriptions changed. Must hold the mutex.
+func (group *StreamGroup) notify() {
+	select {
+	case group.changed <- struct{}{}:
+	default:
+	}
+}
+
+// run merges the events of the group subscriptions into the group events channel until the group is closed.
+func (group *StreamGroup) run() {
+	defer close(group.done)
+
+	var members []*streamGroupEntry
+	drained := map[*streamGroupEntry]bool{}
+
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(group.ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(group.changed)},
+	}
+	const fixedCases = 2
+
+	update := func() {
+		group.mu.Lock()
+		defer group.mu.Unlock()
+
+		members = members[:0]
+		cases = cases[:fixedCases]
+		current := map[*streamGroupEntry]bool{}
+
+		for _, entry := range group.entries {
+			current[entry] = true
+			if entry.started && !drained[entry] {
+				members = append(members, entry)
+				cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(entry.sub.events)})
+			}
+		}
+
+		for entry := range drained {
+			if !current[entry] {
+				delete(drained, entry)
+			}
+		}
+	}
+
+	for {
+		chosen, received, ok := reflect.Select(cases)
+
+		switch {
+		case chosen == 0:
+			return
+		case chosen == 1:
+			update()
+		case !ok:
+			drained[members[chosen-fixedCases]] = true
+			update()
+		default:
+			entry := members[chosen-fixedCases]
+			select {
+			case group.events <- StreamGroupEvent{entry.sub.Query(), received.Interface().(StreamEvent)}:
+			case <-group.ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package faunadb
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func nextGroupEvent(t *testing.T, group *StreamGroup) StreamGroupEvent {
+	select {
+	case event, ok := <-group.StreamEvents():
+		require.True(t, ok, "stream group events closed")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for stream group event")
+		return StreamGroupEvent{}
+	}
+}
+
+func TestStreamGroup(t *testing.T) {
+	released := make(chan struct{})
+	client, _, closeServer := mockedStream(
+		func(w http.ResponseWriter, r *http.Request) {
+			keepOpen(sendEvents(startEvent, versionEvent))(w, r)
+			close(released)
+		},
+		keepOpen(sendEvents(`{"type": "start", "txn": 10, "event": 10}`)),
+	)
+	defer closeServer()
+
+	users := Ref(Collection("users"), "1")
+	posts := Documents(Collection("posts"))
+
+	group := client.StreamGroup()
+	require.NoError(t, group.Add(users))
+	require.NoError(t, group.Add(posts))
+	require.Equal(t, 2, group.Len())
+	require.Equal(t, StreamGroupStatus{Active: 2}, group.Status())
+
+	received := map[string][]int64{}
+	for i := 0; i < 3; i++ {
+		event := nextGroupEvent(t, group)
+		key := ExprString(event.Query)
+		received[key] = append(received[key], event.Event.Txn())
+	}
+
+	require.Equal(t, map[string][]int64{
+		`RefCollection(Collection("users"), "1")`: {1, 2},
+		`Documents(Collection("posts"))`:          {10},
+	}, received)
+
+	require.True(t, group.Remove(users))
+	require.False(t, group.Remove(users))
+	require.Equal(t, 1, group.Len())
+	<-released
+
+	group.Close()
+	_, ok := <-group.StreamEvents()
+	require.False(t, ok)
+}
+
+func TestStreamGroupAdd(t *testing.T) {
+	client, _, closeServer := mockedStream(
+		keepOpen(sendEvents(startEvent)),
+		replyStatus(401, `{"errors": [{"code": "unauthorized", "description": "Unauthorized"}]}`),
+	)
+	defer closeServer()
+
+	group := client.StreamGroup()
+	require.NoError(t, group.Add(RefV{ID: "1"}))
+	require.EqualError(t, group.Add(RefV{ID: "1"}), `stream group already subscribed to Ref("1")`)
+	require.IsType(t, Unauthorized{}, group.Add(RefV{ID: "2"}))
+	require.Equal(t, 1, group.Len())
+
+	group.Close()
+	group.Close()
+	require.EqualError(t, group.Add(RefV{ID: "3"}), "stream group closed")
+}
+
+func TestStreamGroupSharedConfig(t *testing.T) {
+	client, _, closeServer := mockedStream(
+		sendEvents(startEvent),
+		keepOpen(sendEvents(`{"type": "start", "txn": 2, "event": 2}`)),
+	)
+	defer closeServer()
+
+	group := client.StreamGroup(fastReconnect(0))
+	defer group.Close()
+
+	require.NoError(t, group.Add(RefV{ID: "1"}))
+	require.Equal(t, StartEventT, nextGroupEvent(t, group).Event.Type())
+
+	event := nextGroupEvent(t, group)
+	require.Equal(t, ReconnectEventT, event.Event.Type())
+	require.Equal(t, RefV{ID: "1"}, event.Query)
+	require.Equal(t, StreamGroupStatus{Active: 1}, group.Status())
+}
+
+func TestStreamGroupReplacesFinishedSubscriptions(t *testing.T) {
+	client, server, closeServer := mockedStream(sendEvents(startEvent), keepOpen(sendEvents(startEvent)))
+	defer closeServer()
+
+	group := client.StreamGroup()
+	defer group.Close()
+
+	require.NoError(t, group.Add(RefV{ID: "1"}))
+	require.Equal(t, StartEventT, nextGroupEvent(t, group).Event.Type())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for group.Status() != (StreamGroupStatus{Closed: 1}) {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for the subscription to close")
+		time.Sleep(time.Millisecond)
+	}
+
+	require.NoError(t, group.Add(RefV{ID: "1"}))
+	require.Equal(t, StartEventT, nextGroupEvent(t, group).Event.Type())
+	require.Equal(t, StreamGroupStatus{Active: 1}, group.Status())
+	require.Len(t, server.received(), 2)
+}
+
+func waitForRequests(t *testing.T, server *streamServer, count int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.received()) < count {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for stream requests")
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestStreamGroupAddDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	client, server, closeServer := mockedStream(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-release
+			keepOpen(sendEvents(startEvent))(w, r)
+		},
+		keepOpen(sendEvents(`{"type": "start", "txn": 10, "event": 10}`)),
+	)
+	defer closeServer()
+
+	group := client.StreamGroup()
+	defer group.Close()
+
+	added := make(chan error, 1)
+	go func() { added <- group.Add(RefV{ID: "1"}) }()
+	waitForRequests(t, server, 1)
+
+	require.Equal(t, StreamGroupStatus{Idle: 1}, group.Status())
+	require.EqualError(t, group.Add(RefV{ID: "1"}), `stream group already subscribed to Ref("1")`)
+	require.NoError(t, group.Add(RefV{ID: "2"}))
+	require.Equal(t, int64(10), nextGroupEvent(t, group).Event.Txn())
+
+	close(release)
+	require.NoError(t, <-added)
+	require.Equal(t, int64(1), nextGroupEvent(t, group).Event.Txn())
+	require.Equal(t, StreamGroupStatus{Active: 2}, group.Status())
+}
+
+func TestStreamGroupRemoveWhileStarting(t *testing.T) {
+	release := make(chan struct{})
+	client, server, closeServer := mockedStream(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	})
+	defer closeServer()
+	defer close(release)
+
+	group := client.StreamGroup()
+	defer group.Close()
+
+	added := make(chan error, 1)
+	go func() { added <- group.Add(RefV{ID: "1"}) }()
+	waitForRequests(t, server, 1)
+
+	require.True(t, group.Remove(RefV{ID: "1"}))
+	require.Error(t, <-added)
+	require.Zero(t, group.Len())
+}
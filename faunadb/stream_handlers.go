@@ -0,0 +1,112 @@
+package faunadb
+
+import "context"
+
+type streamHandlers struct {
+	start          func(StartEvent) error
+	version        func(VersionEvent) error
+	set            func(SetEvent) error
+	historyRewrite func(HistoryRewriteEvent) error
+	error          func(ErrorEvent) error
+}
+
+// OnStart registers the handler called by Run for start events.
+func (sub *StreamSubscription) OnStart(handler func(StartEvent) error) {
+	sub.handlers.start = handler
+}
+
+// OnVersion registers the handler called by Run for version events.
+func (sub *StreamSubscription) OnVersion(handler func(VersionEvent) error) {
+	sub.handlers.version = handler
+}
+
+// OnSet registers the handler called by Run for set events.
+func (sub *StreamSubscription) OnSet(handler func(SetEvent) error) {
+	sub.handlers.set = handler
+}
+
+// OnHistoryRewrite registers the handler called by Run for history rewrite events.
+func (sub *StreamSubscription) OnHistoryRewrite(handler func(HistoryRewriteEvent) error) {
+	sub.handlers.historyRewrite = handler
+}
+
+// OnError registers the handler called by Run for error events. Without an error handler,
+// Run stops at the first error event and returns its error.
+func (sub *StreamSubscription) OnError(handler func(ErrorEvent) error) {
+	sub.handlers.error = handler
+}
+
+/*
+Run starts the subscription, if not started yet, and dispatches its events to the registered handlers until
+the stream ends. Events without a handler are ignored. For example:
+
+	sub := client.Stream(ref)
+	sub.OnVersion(func(event f.VersionEvent) error {
+		fmt.Println(event.Event())
+		return nil
+	})
+
+	err := sub.Run(ctx)
+
+Run closes the subscription and returns the error of the first handler that fails. It returns the context
+error if the context is cancelled, and nil if the stream is closed otherwise.
+*/
+func (sub *StreamSubscription) Run(ctx context.Context) error {
+	sub.mu.Lock()
+	started := sub.started
+	sub.mu.Unlock()
+
+	if !started {
+		if err := sub.StartContext(ctx); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.events:
+			if !ok {
+				return ctx.Err()
+			}
+
+			if err := sub.dispatch(event); err != nil {
+				sub.Close()
+				return err
+			}
+
+		case <-ctx.Done():
+			sub.Close()
+			return ctx.Err()
+		}
+	}
+}
+
+func (sub *StreamSubscription) dispatch(event StreamEvent) error {
+	handlers := sub.handlers
+
+	switch e := event.(type) {
+	case StartEvent:
+		if handlers.start != nil {
+			return handlers.start(e)
+		}
+	case VersionEvent:
+		if handlers.version != nil {
+			return handlers.version(e)
+		}
+	case SetEvent:
+		if handlers.set != nil {
+			return handlers.set(e)
+		}
+	case HistoryRewriteEvent:
+		if handlers.historyRewrite != nil {
+			return handlers.historyRewrite(e)
+		}
+	case ErrorEvent:
+		if handlers.error != nil {
+			return handlers.error(e)
+		}
+		return e.Error()
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package faunadb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamRunDispatchesEvents(t *testing.T) {
+	client, _, closeServer := mockedStream(sendEvents(
+		startEvent,
+		versionEvent,
+		`{"type": "set", "txn": 3, "event": {"action": "add", "document": {"ref": {"@ref": {"id": "1"}}}}}`,
+		`{"type": "history_rewrite", "txn": 4, "event": {"action": "update"}}`,
+		`{"type": "unknown", "txn": 5, "event": {}}`,
+	))
+	defer closeServer()
+
+	var dispatched []string
+	sub := client.Stream(RefV{ID: "1"})
+	sub.OnStart(func(event StartEvent) error {
+		dispatched = append(dispatched, event.Type())
+		return nil
+	})
+	sub.OnVersion(func(event VersionEvent) error {
+		dispatched = append(dispatched, event.Type())
+		return nil
+	})
+	sub.OnSet(func(event SetEvent) error {
+		dispatched = append(dispatched, event.Type())
+		return nil
+	})
+	sub.OnHistoryRewrite(func(event HistoryRewriteEvent) error {
+		dispatched = append(dispatched, event.Type())
+		return nil
+	})
+
+	require.NoError(t, sub.Run(context.Background()))
+	require.Equal(t, []string{StartEventT, VersionEventT, SetEventT, HistoryRewriteEventT}, dispatched)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamRunStopsOnHandlerError(t *testing.T) {
+	client, _, closeServer := mockedStream(keepOpen(sendEvents(startEvent, versionEvent)))
+	defer closeServer()
+
+	stop := errors.New("stop")
+	sub := client.Stream(RefV{ID: "1"})
+	sub.OnVersion(func(event VersionEvent) error { return stop })
+
+	require.Equal(t, stop, sub.Run(context.Background()))
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamRunErrorEvents(t *testing.T) {
+	client, _, closeServer := mockedStream(sendEvents(startEvent, errorEvent))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.True(t, errors.Is(sub.Run(context.Background()), ErrPermissionDenied))
+
+	client, _, closeServer = mockedStream(sendEvents(startEvent, errorEvent))
+	defer closeServer()
+
+	var handled error
+	handledSub := client.Stream(RefV{ID: "1"})
+	handledSub.OnError(func(event ErrorEvent) error {
+		handled = event.Error()
+		return nil
+	})
+
+	require.NoError(t, handledSub.Run(context.Background()))
+	require.True(t, errors.Is(handled, ErrPermissionDenied))
+}
+
+func TestStreamRunContext(t *testing.T) {
+	client, _, closeServer := mockedStream(keepOpen(sendEvents(startEvent)))
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := client.Stream(RefV{ID: "1"})
+	sub.OnStart(func(event StartEvent) error {
+		cancel()
+		return nil
+	})
+
+	require.Equal(t, context.Canceled, sub.Run(ctx))
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamRunStartedSubscription(t *testing.T) {
+	client, _, closeServer := mockedStream(sendEvents(startEvent, versionEvent))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.NoError(t, sub.Start())
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	var version VersionEvent
+	sub.OnVersion(func(event VersionEvent) error {
+		version = event
+		return nil
+	})
+
+	require.NoError(t, sub.Run(context.Background()))
+	require.Equal(t, int64(2), version.Txn())
+}
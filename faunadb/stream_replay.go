@@ -0,0 +1,195 @@
+package faunadb
+
+import "reflect"
+
+const replayPageSize = 100
+
+type replayPage struct {
+	Data  []ObjectV `fauna:"data"`
+	After Value     `fauna:"after"`
+}
+
+// replayStream delivers the history of the stream query with transaction times after from, up to and
+// including until, as read with Paginate(Events(query)) at the until snapshot. It returns the txn of the
+// last event delivered, or false if the replay failed or the subscription was closed.
+func (client *FaunaClient) replayStream(subscription *StreamSubscription, from, until int64) (lastTxn int64, ok bool) {
+	lastTxn = from
+	var cursor Value = LongV(from + 1)
+	fields := replayFields(subscription.config.Fields)
+
+	for cursor != nil {
+		res, err := client.QueryContext(subscription.ctx, At(until, replayQuery(subscription.query, cursor, fields)))
+		if err != nil {
+			if subscription.ctx.Err() == nil {
+				subscription.emit(ErrorEvent{err: err})
+			}
+			return lastTxn, false
+		}
+
+		var page replayPage
+		if err = res.Get(&page); err != nil {
+			subscription.emit(ErrorEvent{err: err})
+			return lastTxn, false
+		}
+
+		for _, entry := range page.Data {
+			event, err := replayEvent(entry, fields)
+			if err != nil {
+				subscription.emit(ErrorEvent{err: err})
+				return lastTxn, false
+			}
+
+			if txn := event.Txn(); txn <= lastTxn || txn > until {
+				continue
+			}
+
+			if !subscription.emit(event) {
+				return lastTxn, false
+			}
+			lastTxn = event.Txn()
+		}
+
+		cursor = page.After
+	}
+
+	return lastTxn, true
+}
+
+// replayFields returns the fields of the replayed events: the ones selected with Fields, or by default
+// the action, the document and, for set events, the index.
+func replayFields(selected []StreamField) map[StreamField]bool {
+	if len(selected) == 0 {
+		selected = []StreamField{ActionField, DocumentField, IndexField}
+	}
+
+	fields := make(map[StreamField]bool, len(selected))
+	for _, field := range selected {
+		fields[field] = true
+	}
+
+	return fields
+}
+
+// replayQuery reads a page of the history of the query after the cursor. The history does not record the
+// previous version of the documents, so it is read along with each event when diff or prev is selected.
+func replayQuery(query Expr, cursor Value, fields map[StreamField]bool) Expr {
+	page := Paginate(Events(query), After(cursor), Size(replayPageSize))
+	if !fields[DiffField] && !fields[PrevField] {
+		return page
+	}
+
+	return Map(page, Lambda("event", Let().
+		Bind("ref", Select("document", Var("event"))).
+		Bind("ts", Subtract(Select("ts", Var("event")), 1)).
+		In(Merge(Var("event"), Obj{
+			"prev": If(Exists(Var("ref"), TS(Var("ts"))), Get(Var("ref"), TS(Var("ts"))), nil),
+		})),
+	))
+}
+
+// replayEvent converts an entry of the Events history into the equivalent stream event, holding the
+// selected fields only.
+func replayEvent(entry ObjectV, fields map[StreamField]bool) (StreamEvent, error) {
+	var history struct {
+		Ts       int64  `fauna:"ts"`
+		Action   string `fauna:"action"`
+		Document RefV   `fauna:"document"`
+		Data     Value  `fauna:"data"`
+		Prev     Value  `fauna:"prev"`
+	}
+
+	if err := entry.Get(&history); err != nil {
+		return nil, err
+	}
+
+	event := ObjectV{}
+	if fields[ActionField] {
+		event["action"] = StringV(history.Action)
+	}
+
+	switch history.Action {
+	case "add", "remove":
+		if fields[DocumentField] {
+			event["document"] = ObjectV{"ref": history.Document, "ts": LongV(history.Ts)}
+		}
+		if fields[IndexField] {
+			event["index"] = ObjectV{"values": history.Data}
+		}
+
+		return SetEvent{txn: history.Ts, event: event}, nil
+	default:
+		document := ObjectV{"ref": history.Document, "ts": LongV(history.Ts)}
+		if history.Data != nil {
+			document["data"] = history.Data
+		}
+
+		if fields[DocumentField] {
+			event["document"] = document
+		}
+		if fields[PrevField] {
+			prev := history.Prev
+			if prev == nil {
+				prev = NullV{}
+			}
+			event["prev"] = prev
+		}
+		if fields[DiffField] {
+			event["diff"] = replayDiff(history.Prev, document)
+		}
+
+		return VersionEvent{txn: history.Ts, event: event}, nil
+	}
+}
+
+// replayDiff returns the document with the data fields changed since its previous version. Removed
+// fields are null.
+func replayDiff(prev Value, document ObjectV) ObjectV {
+	diff := ObjectV{"ref": document["ref"], "ts": document["ts"]}
+
+	var prevData Value
+	if obj, ok := prev.(ObjectV); ok {
+		prevData = obj["data"]
+	}
+
+	if data := diffValues(prevData, document["data"]); data != nil {
+		diff["data"] = data
+	}
+
+	return diff
+}
+
+// diffValues returns the parts of next that differ from prev, recursing into objects, or nil if they are equal.
+func diffValues(prev, next Value) Value {
+	nextObj, ok := next.(ObjectV)
+	if !ok {
+		switch {
+		case reflect.DeepEqual(prev, next):
+			return nil
+		case next == nil:
+			return NullV{}
+		default:
+			return next
+		}
+	}
+
+	prevObj, _ := prev.(ObjectV)
+	diff := ObjectV{}
+
+	for key, value := range nextObj {
+		if changed := diffValues(prevObj[key], value); changed != nil {
+			diff[key] = changed
+		}
+	}
+
+	for key := range prevObj {
+		if _, ok := nextObj[key]; !ok {
+			diff[key] = NullV{}
+		}
+	}
+
+	if len(diff) == 0 {
+		return nil
+	}
+
+	return diff
+}
@@ -0,0 +1,302 @@
+package faunadb
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// historyServer replies to each query with the next page of history and records the request bodies.
+type historyServer struct {
+	mu     sync.Mutex
+	pages  []string
+	bodies []string
+}
+
+func (s *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	index := len(s.bodies)
+	s.bodies = append(s.bodies, string(body))
+	s.mu.Unlock()
+
+	if index < len(s.pages) {
+		_, _ = w.Write([]byte(`{"resource": ` + s.pages[index] + `}`))
+	} else {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "invalid argument", "description": "No more pages"}]}`))
+	}
+}
+
+func (s *historyServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string{}, s.bodies...)
+}
+
+func historyEvent(ts int64, action string) string {
+	return fmt.Sprintf(`{"ts": %d, "action": "%s", "document": {"@ref": {"id": "1"}}, "data": {"n": %d}}`, ts, action, ts)
+}
+
+func historyPage(after string, events ...string) string {
+	page := `{"data": [` + strings.Join(events, ", ") + `]`
+	if after != "" {
+		page += `, "after": ` + after
+	}
+	return page + `}`
+}
+
+func mockedReplay(history *historyServer, connections ...streamConnection) (*FaunaClient, *streamServer, func()) {
+	server := &streamServer{connections: connections, query: history.ServeHTTP}
+	client, closeServer := mockedClient(server.ServeHTTP)
+	return client, server, closeServer
+}
+
+func TestStreamReplayFrom(t *testing.T) {
+	history := &historyServer{pages: []string{
+		historyPage(`{"ts": 12, "action": "update", "document": {"@ref": {"id": "1"}}}`,
+			historyEvent(5, "create"), historyEvent(6, "update"), historyEvent(8, "update")),
+		historyPage("", historyEvent(12, "update"), historyEvent(25, "delete")),
+	}}
+
+	client, _, closeServer := mockedReplay(history, keepOpen(sendEvents(
+		`{"type": "start", "txn": 20, "event": 20}`,
+		`{"type": "version", "txn": 12, "event": {"action": "update"}}`,
+		`{"type": "version", "txn": 21, "event": {"action": "update"}}`,
+	)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, ReplayFrom(5))
+	require.NoError(t, sub.Start())
+
+	start := nextEvent(t, &sub)
+	require.Equal(t, StartEventT, start.Type())
+	require.Equal(t, int64(20), start.Txn())
+
+	for _, txn := range []int64{6, 8, 12} {
+		event, ok := nextEvent(t, &sub).(VersionEvent)
+		require.True(t, ok)
+		require.Equal(t, txn, event.Txn())
+
+		var document struct {
+			Ts   int64 `fauna:"ts"`
+			Data struct {
+				N int64 `fauna:"n"`
+			} `fauna:"data"`
+		}
+		require.NoError(t, event.DecodeDocument(&document))
+		require.Equal(t, txn, document.Ts)
+		require.Equal(t, txn, document.Data.N)
+	}
+
+	require.Equal(t, int64(21), nextEvent(t, &sub).Txn())
+
+	queries := history.received()
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[0], `"at":20`)
+	require.Contains(t, queries[0], `"after":6`)
+	require.Contains(t, queries[1], `"ts":12`)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReplaySetEvents(t *testing.T) {
+	history := &historyServer{pages: []string{
+		historyPage("", `{"ts": 3, "action": "add", "document": {"@ref": {"id": "1"}}, "data": ["a"]}`),
+	}}
+
+	client, _, closeServer := mockedReplay(history, keepOpen(sendEvents(`{"type": "start", "txn": 4, "event": 4}`)))
+	defer closeServer()
+
+	sub := client.Stream(Match(Index("all")), ReplayFrom(1))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	event, ok := nextEvent(t, &sub).(SetEvent)
+	require.True(t, ok)
+	require.Equal(t, int64(3), event.Txn())
+
+	action, err := event.Action()
+	require.NoError(t, err)
+	require.Equal(t, "add", action)
+
+	index, err := event.Index()
+	require.NoError(t, err)
+	require.Equal(t, ArrayV{StringV("a")}, index.Values)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReplayAfterReconnect(t *testing.T) {
+	history := &historyServer{pages: []string{
+		historyPage(""),
+		historyPage("", historyEvent(3, "update")),
+	}}
+
+	client, _, closeServer := mockedReplay(history,
+		sendEvents(startEvent, versionEvent),
+		keepOpen(sendEvents(
+			`{"type": "start", "txn": 4, "event": 4}`,
+			`{"type": "version", "txn": 3, "event": {"action": "update"}}`,
+			`{"type": "version", "txn": 5, "event": {"action": "update"}}`,
+		)),
+	)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, ReplayFrom(1), fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, int64(2), nextEvent(t, &sub).Txn())
+
+	reconnect, ok := nextEvent(t, &sub).(ReconnectEvent)
+	require.True(t, ok)
+	require.Equal(t, int64(4), reconnect.Txn())
+
+	require.Equal(t, int64(3), nextEvent(t, &sub).Txn())
+
+	require.Equal(t, int64(5), nextEvent(t, &sub).Txn())
+
+	queries := history.received()
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[1], `"after":3`)
+	require.Contains(t, queries[1], `"at":4`)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReplayFields(t *testing.T) {
+	history := &historyServer{pages: []string{
+		historyPage("", `{"ts": 3, "action": "update", "document": {"@ref": {"id": "1"}}, "data": {"n": 3, "m": 1},
+			"prev": {"ref": {"@ref": {"id": "1"}}, "ts": 2, "data": {"n": 2, "m": 1, "o": 1}}}`),
+	}}
+
+	client, _, closeServer := mockedReplay(history, keepOpen(sendEvents(`{"type": "start", "txn": 4, "event": 4}`)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, ReplayFrom(1), Fields(DiffField, PrevField))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	event, ok := nextEvent(t, &sub).(VersionEvent)
+	require.True(t, ok)
+
+	diff, err := event.Diff()
+	require.NoError(t, err)
+	require.Equal(t, ObjectV{
+		"ref":  RefV{ID: "1"},
+		"ts":   LongV(3),
+		"data": ObjectV{"n": LongV(3), "o": NullV{}},
+	}, diff)
+
+	prev, err := event.Prev()
+	require.NoError(t, err)
+	var ts int64
+	require.NoError(t, prev.At(ObjKey("ts")).Get(&ts))
+	require.Equal(t, int64(2), ts)
+
+	_, err = event.Document()
+	require.Error(t, err)
+
+	queries := history.received()
+	require.Len(t, queries, 1)
+	require.Contains(t, queries[0], `"merge"`)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReplayFailure(t *testing.T) {
+	client, _, closeServer := mockedReplay(&historyServer{}, keepOpen(sendEvents(startEvent)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, ReplayFrom(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	errEvent, ok := nextEvent(t, &sub).(ErrorEvent)
+	require.True(t, ok)
+	require.Error(t, errEvent.Error())
+
+	requireClosed(t, &sub)
+}
+
+func TestStreamReconnectReplaysMissedEvents(t *testing.T) {
+	history := &historyServer{pages: []string{
+		historyPage("", historyEvent(3, "update")),
+	}}
+
+	client, _, closeServer := mockedReplay(history,
+		sendEvents(startEvent, versionEvent),
+		keepOpen(sendEvents(
+			`{"type": "start", "txn": 4, "event": 4}`,
+			`{"type": "version", "txn": 5, "event": {"action": "update"}}`,
+		)),
+	)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, int64(2), nextEvent(t, &sub).Txn())
+
+	reconnect, ok := nextEvent(t, &sub).(ReconnectEvent)
+	require.True(t, ok)
+	require.Equal(t, int64(4), reconnect.Txn())
+
+	missed, ok := nextEvent(t, &sub).(VersionEvent)
+	require.True(t, ok)
+	require.Equal(t, int64(3), missed.Txn())
+
+	require.Equal(t, int64(5), nextEvent(t, &sub).Txn())
+
+	queries := history.received()
+	require.Len(t, queries, 1)
+	require.Contains(t, queries[0], `"after":3`)
+	require.Contains(t, queries[0], `"at":4`)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReplayRunHandlers(t *testing.T) {
+	var txns []int64
+
+	history := &historyServer{pages: []string{
+		historyPage("", historyEvent(2, "create"), historyEvent(3, "update")),
+	}}
+
+	client, _, closeServer := mockedReplay(history, sendEvents(
+		`{"type": "start", "txn": 4, "event": 4}`,
+		`{"type": "version", "txn": 5, "event": {"action": "update"}}`,
+	))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, ReplayFrom(1))
+	sub.OnStart(func(event StartEvent) error {
+		txns = append(txns, event.Txn())
+		return nil
+	})
+	sub.OnVersion(func(event VersionEvent) error {
+		require.NotEmpty(t, txns, "version event before the start event")
+		txns = append(txns, event.Txn())
+		return nil
+	})
+
+	require.NoError(t, sub.Run(context.Background()))
+	require.Equal(t, []int64{4, 2, 3, 5}, txns)
+}
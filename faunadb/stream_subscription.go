@@ -1,32 +1,44 @@
 package faunadb
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 )
 
 // StreamSubscription dispatches events received to the registered listener functions.
 // New subscriptions must be constructed via the FaunaClient stream method.
 type StreamSubscription struct {
-	mu     sync.Mutex
-	query  Expr
-	config streamConfig
-	client *FaunaClient
-	status StreamConnectionStatus
-	events chan StreamEvent
-	closed chan bool
+	mu       *sync.Mutex
+	query    Expr
+	config   streamConfig
+	client   *FaunaClient
+	status   StreamConnectionStatus
+	started  bool
+	lastTxn  int64
+	dropped  int64
+	overrun  bool
+	events   chan StreamEvent
+	handlers streamHandlers
+	ctx      context.Context
+	cancel   context.CancelFunc
+
+	span       Span
+	reconnects int
 }
 
 func newSubscription(client *FaunaClient, query Expr, config ...StreamConfig) StreamSubscription {
 	sub := StreamSubscription{
+		mu:    &sync.Mutex{},
 		query: query,
 		config: streamConfig{
-			[]StreamField{},
+			Fields: []StreamField{},
 		},
 		client: client,
 		status: StreamConnIdle,
 		events: make(chan StreamEvent),
-		closed: make(chan bool),
+		span:   noopSpan{},
 	}
 	for _, fn := range config {
 		fn(&sub)
@@ -63,32 +75,146 @@ func (sub *StreamSubscription) Status() StreamConnectionStatus {
 	return sub.status
 }
 
+// Start initiates the stream subscription.
 func (sub *StreamSubscription) Start() (err error) {
+	return sub.StartContext(context.Background())
+}
+
+// StartContext initiates the stream subscription bound to the given context. Cancelling the context
+// closes the subscription, as Close does: the stream connection is released, the events channel is
+// closed and the status becomes StreamConnClosed.
+func (sub *StreamSubscription) StartContext(ctx context.Context) (err error) {
 	sub.mu.Lock()
 	defer sub.mu.Unlock()
 
-	if sub.status != StreamConnIdle {
+	if sub.started {
 		err = errors.New("stream subscription already started")
 	} else {
+		sub.started = true
 		sub.status = StreamConnActive
+		sub.ctx, sub.cancel = context.WithCancel(ctx)
 		if err = sub.client.startStream(sub); err != nil {
 			sub.status = StreamConnError
+			sub.cancel()
 		}
 	}
 	return
 }
 
-
 func (sub *StreamSubscription) Close() {
 	sub.mu.Lock()
 	defer sub.mu.Unlock()
-	if sub.status == StreamConnActive {
+	if sub.status == StreamConnActive || (sub.started && sub.status == StreamConnIdle) {
 		sub.status = StreamConnClosed
-		close(sub.closed)
-		close(sub.events)
+		sub.cancel()
 	}
 }
 
 func (sub *StreamSubscription) StreamEvents() <-chan StreamEvent {
 	return sub.events
-}
\ No newline at end of file
+}
+
+// DroppedEvents returns the number of events discarded because the events buffer was full.
+func (sub *StreamSubscription) DroppedEvents() int64 {
+	return atomic.LoadInt64(&sub.dropped)
+}
+
+// setStatus updates the status of a running subscription. It returns false if the subscription was closed.
+func (sub *StreamSubscription) setStatus(status StreamConnectionStatus) bool {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.status == StreamConnClosed {
+		return false
+	}
+	sub.status = status
+	return true
+}
+
+func (sub *StreamSubscription) lastSeenTxn() int64 {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.lastTxn
+}
+
+// emit delivers the event to the events channel. It returns false if the subscription was closed before
+// the event could be delivered.
+func (sub *StreamSubscription) emit(event StreamEvent) bool {
+	if errEvent, ok := event.(ErrorEvent); ok {
+		sub.span.RecordError(errEvent.Error())
+	}
+
+	if txn := event.Txn(); txn > 0 {
+		sub.mu.Lock()
+		if txn > sub.lastTxn {
+			sub.lastTxn = txn
+		}
+		sub.mu.Unlock()
+	}
+
+	switch sub.config.Overflow {
+	case StreamOverflowDropOldest:
+		for {
+			select {
+			case sub.events <- event:
+				return true
+			default:
+			}
+
+			if cap(sub.events) == 0 {
+				// Nothing buffered to discard, the consumer is not ready for the new event
+				atomic.AddInt64(&sub.dropped, 1)
+				return true
+			}
+
+			select {
+			case <-sub.events:
+				atomic.AddInt64(&sub.dropped, 1)
+			default:
+			}
+		}
+
+	case StreamOverflowClose:
+		select {
+		case sub.events <- event:
+			return true
+		default:
+			atomic.AddInt64(&sub.dropped, 1)
+			sub.overrun = true
+			return false
+		}
+	}
+
+	select {
+	case sub.events <- event:
+		return true
+	case <-sub.ctx.Done():
+		return false
+	}
+}
+
+// finish releases the subscription resources once no more events can be delivered. Subscriptions
+// closed or whose context was cancelled end as StreamConnClosed, otherwise with the given status.
+// Subscriptions that overflowed their events buffer deliver ErrStreamOverflow before closing the events.
+func (sub *StreamSubscription) finish(status StreamConnectionStatus) {
+	if sub.overrun && sub.setStatus(StreamConnError) {
+		status = StreamConnError
+		select {
+		case sub.events <- ErrorEvent{err: ErrStreamOverflow}:
+		case <-sub.ctx.Done():
+		}
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.ctx.Err() != nil {
+		sub.status = StreamConnClosed
+	} else {
+		sub.status = status
+	}
+	sub.cancel()
+
+	sub.span.SetAttribute(AttributeStreamStatus, streamStatusName(sub.status))
+	sub.span.End()
+
+	close(sub.events)
+}
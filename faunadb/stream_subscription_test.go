@@ -0,0 +1,477 @@
+package faunadb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type streamConnection func(w http.ResponseWriter, r *http.Request)
+
+// streamServer replies to each stream request with the next connection. Requests beyond the given
+// connections are held open until canceled. Other requests are served by the query handler, if any.
+type streamServer struct {
+	mu          sync.Mutex
+	connections []streamConnection
+	requests    []*http.Request
+	query       http.HandlerFunc
+}
+
+func (s *streamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/stream" {
+		if s.query != nil {
+			s.query(w, r)
+		} else {
+			// Reconnected streams replay the events missed while disconnected, none by default
+			_, _ = w.Write([]byte(`{"resource": {"data": []}}`))
+		}
+		return
+	}
+
+	s.mu.Lock()
+	index := len(s.requests)
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	if index < len(s.connections) {
+		s.connections[index](w, r)
+	} else {
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}
+}
+
+func (s *streamServer) received() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request{}, s.requests...)
+}
+
+func sendEvents(events ...string) streamConnection {
+	return func(w http.ResponseWriter, r *http.Request) {
+		for _, event := range events {
+			_, _ = w.Write([]byte(event + "\n"))
+			w.(http.Flusher).Flush()
+		}
+	}
+}
+
+func keepOpen(connection streamConnection) streamConnection {
+	return func(w http.ResponseWriter, r *http.Request) {
+		connection(w, r)
+		<-r.Context().Done()
+	}
+}
+
+func replyStatus(status int, body string) streamConnection {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func mockedStream(connections ...streamConnection) (*FaunaClient, *streamServer, func()) {
+	server := &streamServer{connections: connections}
+	client, closeServer := mockedClient(server.ServeHTTP)
+	return client, server, closeServer
+}
+
+func fastReconnect(maxAttempts int) StreamConfig {
+	return Reconnect(ReconnectOptions{MaxAttempts: maxAttempts, Backoff: Backoff{Initial: time.Millisecond}})
+}
+
+func nextEvent(t *testing.T, sub *StreamSubscription) StreamEvent {
+	select {
+	case event, ok := <-sub.StreamEvents():
+		require.True(t, ok, "stream events closed")
+		return event
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for stream event")
+		return nil
+	}
+}
+
+func requireClosed(t *testing.T, sub *StreamSubscription) {
+	select {
+	case event, ok := <-sub.StreamEvents():
+		require.False(t, ok, "unexpected event %s", event)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timed out waiting for stream events to close")
+	}
+}
+
+const (
+	startEvent   = `{"type": "start", "txn": 1, "event": 1}`
+	versionEvent = `{"type": "version", "txn": 2, "event": {"action": "update", "document": {"data": {"n": 2}}}}`
+	errorEvent   = `{"type": "error", "txn": 3, "event": {"code": "permission denied", "description": "Denied"}}`
+)
+
+func TestStreamClosesWhenConnectionEnds(t *testing.T) {
+	client, server, closeServer := mockedStream(sendEvents(startEvent, versionEvent))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, VersionEventT, nextEvent(t, &sub).Type())
+	requireClosed(t, &sub)
+
+	require.Equal(t, StreamConnClosed, sub.Status())
+	require.Len(t, server.received(), 1)
+}
+
+func TestStreamClose(t *testing.T) {
+	client, _, closeServer := mockedStream()
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+	require.Equal(t, StreamConnActive, sub.Status())
+
+	sub.Close()
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamReconnect(t *testing.T) {
+	var sub StreamSubscription
+	reconnectingStatus := make(chan StreamConnectionStatus, 1)
+
+	client, server, closeServer := mockedStream(
+		sendEvents(startEvent, versionEvent),
+		keepOpen(func(w http.ResponseWriter, r *http.Request) {
+			reconnectingStatus <- sub.Status()
+			sendEvents(
+				`{"type": "start", "txn": 4, "event": 4}`,
+				versionEvent,
+				`{"type": "version", "txn": 5, "event": {"action": "delete"}}`,
+			)(w, r)
+		}),
+	)
+	defer closeServer()
+
+	sub = client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, int64(2), nextEvent(t, &sub).Txn())
+
+	reconnect, ok := nextEvent(t, &sub).(ReconnectEvent)
+	require.True(t, ok)
+	require.Equal(t, int64(4), reconnect.Txn())
+	require.Equal(t, 1, reconnect.Attempts())
+	require.NotNil(t, reconnect.Cause())
+
+	require.Equal(t, int64(5), nextEvent(t, &sub).Txn())
+	require.Equal(t, StreamConnIdle, <-reconnectingStatus)
+	require.Equal(t, StreamConnActive, sub.Status())
+	require.Equal(t, "2", server.received()[1].Header.Get(headerLastSeenTxn))
+
+	sub.Close()
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamReconnectRetriesFailedRequests(t *testing.T) {
+	client, server, closeServer := mockedStream(
+		sendEvents(startEvent),
+		replyStatus(503, `{"errors": [{"code": "unavailable", "description": "Unavailable"}]}`),
+		keepOpen(sendEvents(`{"type": "start", "txn": 2, "event": 2}`)),
+	)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(3))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	reconnect, ok := nextEvent(t, &sub).(ReconnectEvent)
+	require.True(t, ok)
+	require.Equal(t, 2, reconnect.Attempts())
+	require.Len(t, server.received(), 3)
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamReconnectGivesUp(t *testing.T) {
+	unavailable := replyStatus(503, `{"errors": [{"code": "unavailable", "description": "Unavailable"}]}`)
+	client, server, closeServer := mockedStream(sendEvents(startEvent), unavailable, unavailable)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(2))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	errEvent, ok := nextEvent(t, &sub).(ErrorEvent)
+	require.True(t, ok)
+	require.IsType(t, Unavailable{}, errEvent.Error())
+
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnError, sub.Status())
+	require.Len(t, server.received(), 3)
+}
+
+func TestStreamReconnectStopsOnUnauthorized(t *testing.T) {
+	client, server, closeServer := mockedStream(
+		sendEvents(startEvent),
+		replyStatus(401, `{"errors": [{"code": "unauthorized", "description": "Unauthorized"}]}`),
+	)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, ErrorEventT, nextEvent(t, &sub).Type())
+
+	requireClosed(t, &sub)
+	require.Len(t, server.received(), 2)
+}
+
+func TestStreamDoesNotReconnectAfterErrorEvent(t *testing.T) {
+	client, server, closeServer := mockedStream(sendEvents(startEvent, errorEvent))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, ErrorEventT, nextEvent(t, &sub).Type())
+
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+	require.Len(t, server.received(), 1)
+}
+
+func TestStreamStartContext(t *testing.T) {
+	released := make(chan struct{})
+	client, _, closeServer := mockedStream(func(w http.ResponseWriter, r *http.Request) {
+		keepOpen(sendEvents(startEvent))(w, r)
+		close(released)
+	})
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.NoError(t, sub.StartContext(ctx))
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	cancel()
+	requireClosed(t, &sub)
+	<-released
+
+	require.Equal(t, StreamConnClosed, sub.Status())
+	require.EqualError(t, sub.Start(), "stream subscription already started")
+}
+
+func TestStreamStartContextWhileReconnecting(t *testing.T) {
+	client, _, closeServer := mockedStream(sendEvents(startEvent))
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub := client.Stream(RefV{ID: "1"}, Reconnect(ReconnectOptions{Backoff: Backoff{Initial: time.Hour}}))
+	require.NoError(t, sub.StartContext(ctx))
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	for sub.Status() != StreamConnIdle {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnClosed, sub.Status())
+}
+
+func TestStreamStartContextCanceled(t *testing.T) {
+	client, server, closeServer := mockedStream()
+	defer closeServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	sub := client.Stream(RefV{ID: "1"})
+	require.Equal(t, context.Canceled, sub.StartContext(ctx))
+	require.Equal(t, StreamConnError, sub.Status())
+	require.Empty(t, server.received())
+}
+
+func waitForDropped(t *testing.T, sub *StreamSubscription, dropped int64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for sub.DroppedEvents() < dropped {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for dropped events")
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func versionEvents(count int) []string {
+	events := []string{startEvent}
+	for txn := 2; txn < count+2; txn++ {
+		events = append(events, fmt.Sprintf(`{"type": "version", "txn": %d, "event": {"action": "update"}}`, txn))
+	}
+	return events
+}
+
+func TestStreamBufferSize(t *testing.T) {
+	client, _, closeServer := mockedStream(keepOpen(sendEvents(versionEvents(2)...)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, BufferSize(3))
+	require.NoError(t, sub.Start())
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(sub.events) < 3 {
+		require.True(t, time.Now().Before(deadline), "timed out waiting for buffered events")
+		time.Sleep(time.Millisecond)
+	}
+
+	require.Equal(t, int64(1), nextEvent(t, &sub).Txn())
+	require.Equal(t, int64(2), nextEvent(t, &sub).Txn())
+	require.Equal(t, int64(3), nextEvent(t, &sub).Txn())
+	require.Zero(t, sub.DroppedEvents())
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamOverflowDropOldest(t *testing.T) {
+	client, _, closeServer := mockedStream(keepOpen(sendEvents(versionEvents(4)...)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, BufferSize(2), Overflow(StreamOverflowDropOldest))
+	require.NoError(t, sub.Start())
+
+	waitForDropped(t, &sub, 3)
+
+	require.Equal(t, int64(4), nextEvent(t, &sub).Txn())
+	require.Equal(t, int64(5), nextEvent(t, &sub).Txn())
+	require.Equal(t, StreamConnActive, sub.Status())
+
+	sub.Close()
+	requireClosed(t, &sub)
+	require.Equal(t, int64(3), sub.DroppedEvents())
+}
+
+func TestStreamOverflowClose(t *testing.T) {
+	released := make(chan struct{})
+	client, _, closeServer := mockedStream(func(w http.ResponseWriter, r *http.Request) {
+		keepOpen(sendEvents(versionEvents(2)...))(w, r)
+		close(released)
+	})
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, BufferSize(1), Overflow(StreamOverflowClose))
+	require.NoError(t, sub.Start())
+
+	waitForDropped(t, &sub, 1)
+	<-released
+
+	require.Equal(t, StreamConnError, sub.Status())
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	errEvent, ok := nextEvent(t, &sub).(ErrorEvent)
+	require.True(t, ok)
+	require.Equal(t, ErrStreamOverflow, errEvent.Error())
+
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnError, sub.Status())
+	require.Equal(t, int64(1), sub.DroppedEvents())
+}
+
+// heartbeats sends empty frames at the given interval for the given duration.
+func heartbeats(interval, duration time.Duration) streamConnection {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		deadline := time.After(duration)
+
+		for {
+			select {
+			case <-ticker.C:
+				_, _ = w.Write([]byte("\n"))
+				w.(http.Flusher).Flush()
+			case <-deadline:
+				return
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
+
+func TestStreamIdleTimeout(t *testing.T) {
+	client, _, closeServer := mockedStream(keepOpen(sendEvents(startEvent)))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, IdleTimeout(50*time.Millisecond))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+
+	errEvent, ok := nextEvent(t, &sub).(ErrorEvent)
+	require.True(t, ok)
+	require.Equal(t, ErrStreamIdle, errEvent.Error())
+
+	requireClosed(t, &sub)
+	require.Equal(t, StreamConnError, sub.Status())
+}
+
+func TestStreamIdleTimeoutHeartbeats(t *testing.T) {
+	client, _, closeServer := mockedStream(func(w http.ResponseWriter, r *http.Request) {
+		sendEvents(startEvent)(w, r)
+		heartbeats(10*time.Millisecond, 200*time.Millisecond)(w, r)
+		keepOpen(sendEvents(versionEvent))(w, r)
+	})
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, IdleTimeout(100*time.Millisecond))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, VersionEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, StreamConnActive, sub.Status())
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
+
+func TestStreamIdleTimeoutReconnects(t *testing.T) {
+	client, server, closeServer := mockedStream(
+		keepOpen(sendEvents(startEvent, versionEvent)),
+		func(w http.ResponseWriter, r *http.Request) {
+			sendEvents(`{"type": "start", "txn": 4, "event": 4}`, `{"type": "version", "txn": 5, "event": {}}`)(w, r)
+			heartbeats(10*time.Millisecond, time.Minute)(w, r)
+		},
+	)
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, IdleTimeout(50*time.Millisecond), fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, VersionEventT, nextEvent(t, &sub).Type())
+
+	errEvent, ok := nextEvent(t, &sub).(ErrorEvent)
+	require.True(t, ok)
+	require.Equal(t, ErrStreamIdle, errEvent.Error())
+
+	reconnect, ok := nextEvent(t, &sub).(ReconnectEvent)
+	require.True(t, ok)
+	require.Equal(t, ErrStreamIdle, reconnect.Cause())
+
+	require.Equal(t, int64(5), nextEvent(t, &sub).Txn())
+	require.Equal(t, StreamConnActive, sub.Status())
+	require.Equal(t, "2", server.received()[1].Header.Get(headerLastSeenTxn))
+
+	sub.Close()
+	requireClosed(t, &sub)
+}
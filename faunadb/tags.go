@@ -19,33 +19,45 @@ func fieldName(field reflect.StructField) string {
 	return name
 }
 
+// fieldTag holds the options of a fauna struct field tag
+type fieldTag struct {
+	name      string
+	named     bool // the tag sets the field name
+	ignore    bool
+	omitempty bool
+	inline    bool
+}
+
 // parseTag interprets fauna struct field tags
-func parseTag(field reflect.StructField) (name string, ignore, omitempty bool, err error) {
+func parseTag(field reflect.StructField) (tag fieldTag, err error) {
 	s := field.Tag.Get(faunaTag)
 	parts := strings.Split(s, ",")
 	if s == "" {
-		return field.Name, false, false, nil
+		return fieldTag{name: field.Name}, nil
 	}
 
 	if parts[0] == "-" {
-		return "", true, false, nil
+		return fieldTag{ignore: true}, nil
 	}
 
 	if len(parts) > 1 {
 		for _, p := range parts[1:] {
 			switch p {
 			case "omitempty":
-				omitempty = true
+				tag.omitempty = true
+			case "inline":
+				tag.inline = true
 			default:
 				err = fmt.Errorf("fauna: struct tag has invalid option: %q", p)
-				return "", false, false, err
+				return fieldTag{}, err
 			}
 		}
 	}
 	if parts[0] != "" {
-		name = parts[0]
+		tag.name = parts[0]
+		tag.named = true
 	} else {
-		name = field.Name
+		tag.name = field.Name
 	}
 
 	return
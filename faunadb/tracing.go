@@ -0,0 +1,151 @@
+package faunadb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Tracer starts the spans traced by a FaunaClient. It is implemented by adapters to a tracing library,
+// such as OpenTelemetry, so the driver does not depend on any of them.
+type Tracer interface {
+	// Start starts a span with the given name as a child of the span in ctx, if any, and returns
+	// a context holding the new span.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a traced operation started by a Tracer.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// SpanContext identifies a span in a W3C trace context.
+type SpanContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Sampled bool
+}
+
+// IsValid returns true if both the trace and span ids are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != [16]byte{} && sc.SpanID != [8]byte{}
+}
+
+// Traceparent returns the W3C traceparent header value of the span context.
+func (sc SpanContext) Traceparent() string {
+	flags := 0
+	if sc.Sampled {
+		flags = 1
+	}
+	return fmt.Sprintf("00-%x-%x-%02x", sc.TraceID, sc.SpanID, flags)
+}
+
+// Span names and attributes recorded by the FaunaClient.
+const (
+	SpanQuery      = "faunadb.Query"
+	SpanBatchQuery = "faunadb.BatchQuery"
+	SpanStream     = "faunadb.Stream"
+
+	AttributeFQL             = "fauna.fql"
+	AttributeStatusCode      = "http.status_code"
+	AttributeTxnTime         = "fauna.txn_time"
+	AttributeComputeOps      = "fauna.compute_ops"
+	AttributeByteReadOps     = "fauna.byte_read_ops"
+	AttributeByteWriteOps    = "fauna.byte_write_ops"
+	AttributeQueryTime       = "fauna.query_time_ms"
+	AttributeStreamReconnect = "fauna.stream.reconnects"
+	AttributeStreamStatus    = "fauna.stream.status"
+)
+
+// Tracing configures a Tracer for a FaunaClient. A span is started for every Query, BatchQuery and stream
+// subscription, from its start until it is closed. The span context is sent to FaunaDB in the traceparent
+// header, unless the query sets one with Traceparent. Spans record the HTTP status code, the transaction
+// time, the query time and the compute and byte ops reported by FaunaDB, including for failed queries. The
+// query itself is only recorded with TraceFQL.
+func Tracing(tracer Tracer) ClientConfig {
+	return func(cli *FaunaClient) { cli.tracer = tracer }
+}
+
+// TraceFQL configures the spans started by the client Tracer to record the FQL representation of the query
+// in the AttributeFQL attribute. Query literals are recorded as is: secrets, such as Login passwords or
+// CreateKey and Credentials payloads, and document data are sent to the tracing backend. Only enable it
+// when the traced queries hold no sensitive values.
+func TraceFQL() ClientConfig {
+	return func(cli *FaunaClient) { cli.traceFQL = true }
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SpanContext() SpanContext         { return SpanContext{} }
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) RecordError(error)                {}
+func (noopSpan) End()                             {}
+
+// startSpan starts a span for the request, if the client has a Tracer, and sets its traceparent header.
+func (client *FaunaClient) startSpan(ctx context.Context, name string, request *Request) (context.Context, Span) {
+	if client.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	ctx, span := client.tracer.Start(ctx, name)
+	if client.traceFQL {
+		span.SetAttribute(AttributeFQL, ExprString(request.Expr))
+	}
+	if request.Headers != nil {
+		injectTraceparent(request.Headers, span)
+	}
+
+	return ctx, span
+}
+
+func injectTraceparent(headers http.Header, span Span) {
+	if sc := span.SpanContext(); sc.IsValid() && headers.Get(headerTraceparent) == "" {
+		headers.Set(headerTraceparent, sc.Traceparent())
+	}
+}
+
+// recordResponse records the status code and the stats headers of the response, or its error, in the span.
+func recordResponse(span Span, response *Response, err error) {
+	// Failed queries answered by FaunaDB also report their stats
+	if response != nil {
+		if response.StatusCode != 0 {
+			span.SetAttribute(AttributeStatusCode, response.StatusCode)
+		}
+
+		for header, attribute := range map[string]string{
+			headerTxnTime:      AttributeTxnTime,
+			headerComputeOps:   AttributeComputeOps,
+			headerByteReadOps:  AttributeByteReadOps,
+			headerByteWriteOps: AttributeByteWriteOps,
+			headerQueryTime:    AttributeQueryTime,
+		} {
+			if value, err := strconv.ParseInt(response.Headers.Get(header), 10, 64); err == nil {
+				span.SetAttribute(attribute, value)
+			}
+		}
+	}
+
+	if err != nil {
+		if faunaErr, ok := err.(FaunaError); ok {
+			span.SetAttribute(AttributeStatusCode, faunaErr.Status())
+		}
+		span.RecordError(err)
+	}
+}
+
+func streamStatusName(status StreamConnectionStatus) string {
+	switch status {
+	case StreamConnIdle:
+		return "idle"
+	case StreamConnActive:
+		return "active"
+	case StreamConnClosed:
+		return "closed"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,202 @@
+package faunadb
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSpan struct {
+	mu         sync.Mutex
+	name       string
+	ctx        SpanContext
+	attributes map[string]interface{}
+	errors     []error
+	ended      bool
+}
+
+func (span *testSpan) SpanContext() SpanContext { return span.ctx }
+
+func (span *testSpan) SetAttribute(key string, value interface{}) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.attributes[key] = value
+}
+
+func (span *testSpan) RecordError(err error) {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.errors = append(span.errors, err)
+}
+
+func (span *testSpan) End() {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	span.ended = true
+}
+
+func (span *testSpan) attribute(key string) interface{} {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+	return span.attributes[key]
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tracer *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	span := &testSpan{name: name, attributes: map[string]interface{}{}}
+	span.ctx.TraceID[15] = 1
+	span.ctx.SpanID[7] = byte(len(tracer.spans) + 1)
+	span.ctx.Sampled = true
+
+	tracer.spans = append(tracer.spans, span)
+	return ctx, span
+}
+
+func TestSpanContextTraceparent(t *testing.T) {
+	sc := SpanContext{
+		TraceID: [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Sampled: true,
+	}
+
+	require.True(t, sc.IsValid())
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", sc.Traceparent())
+	require.False(t, SpanContext{}.IsValid())
+}
+
+func TestTracingQuery(t *testing.T) {
+	var traceparent string
+	tracer := &testTracer{}
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get(headerTraceparent)
+		w.Header().Set(headerTxnTime, "42")
+		w.Header().Set(headerComputeOps, "1")
+		w.Header().Set(headerByteReadOps, "2")
+		w.Header().Set(headerByteWriteOps, "3")
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}, Tracing(tracer), TraceFQL())
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2))
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	require.Equal(t, SpanQuery, span.name)
+	require.True(t, span.ended)
+	require.Equal(t, span.ctx.Traceparent(), traceparent)
+	require.Equal(t, map[string]interface{}{
+		AttributeFQL:          "Add(40, 2)",
+		AttributeStatusCode:   http.StatusOK,
+		AttributeTxnTime:      int64(42),
+		AttributeComputeOps:   int64(1),
+		AttributeByteReadOps:  int64(2),
+		AttributeByteWriteOps: int64(3),
+	}, span.attributes)
+}
+
+func TestTracingOmitsFQLByDefault(t *testing.T) {
+	tracer := &testTracer{}
+
+	client, closeServer := mockedClient(slowHandler(0), Tracing(tracer))
+	defer closeServer()
+
+	_, err := client.Query(Login(Ref(Collection("users"), "1"), Obj{"password": "secret"}))
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	require.NotContains(t, tracer.spans[0].attributes, AttributeFQL)
+	require.Equal(t, http.StatusOK, tracer.spans[0].attributes[AttributeStatusCode])
+}
+
+func TestTracingKeepsExplicitTraceparent(t *testing.T) {
+	var traceparent string
+	explicit := "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get(headerTraceparent)
+		_, _ = w.Write([]byte(`{"resource": 42}`))
+	}, Tracing(&testTracer{}))
+	defer closeServer()
+
+	_, err := client.Query(Add(40, 2), Traceparent(explicit))
+	require.NoError(t, err)
+	require.Equal(t, explicit, traceparent)
+}
+
+func TestTracingBatchQueryError(t *testing.T) {
+	tracer := &testTracer{}
+
+	client, closeServer := mockedClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerComputeOps, "2")
+		w.Header().Set(headerByteReadOps, "5")
+		w.Header().Set(headerByteWriteOps, "0")
+		w.Header().Set(headerQueryTime, "12")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"errors": [{"code": "invalid argument", "description": "Invalid"}]}`))
+	}, Tracing(tracer))
+	defer closeServer()
+
+	_, err := client.BatchQuery([]Expr{Add(1, 1), Add(2, 2)})
+	require.Error(t, err)
+
+	require.Len(t, tracer.spans, 1)
+	span := tracer.spans[0]
+	require.Equal(t, SpanBatchQuery, span.name)
+	require.True(t, span.ended)
+	require.Equal(t, map[string]interface{}{
+		AttributeStatusCode:   http.StatusBadRequest,
+		AttributeComputeOps:   int64(2),
+		AttributeByteReadOps:  int64(5),
+		AttributeByteWriteOps: int64(0),
+		AttributeQueryTime:    int64(12),
+	}, span.attributes)
+	require.Equal(t, []error{err}, span.errors)
+}
+
+func TestTracingStream(t *testing.T) {
+	tracer := &testTracer{}
+	server := &streamServer{connections: []streamConnection{
+		sendEvents(startEvent, versionEvent),
+		keepOpen(sendEvents(`{"type": "start", "txn": 4, "event": 4}`)),
+	}}
+
+	client, closeServer := mockedClient(server.ServeHTTP, Tracing(tracer))
+	defer closeServer()
+
+	sub := client.Stream(RefV{ID: "1"}, fastReconnect(0))
+	require.NoError(t, sub.Start())
+
+	require.Equal(t, StartEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, VersionEventT, nextEvent(t, &sub).Type())
+	require.Equal(t, ReconnectEventT, nextEvent(t, &sub).Type())
+
+	sub.Close()
+	requireClosed(t, &sub)
+
+	// The events missed while reconnecting are replayed with a query
+	require.Len(t, tracer.spans, 2)
+	require.Equal(t, SpanQuery, tracer.spans[1].name)
+
+	span := tracer.spans[0]
+	require.Equal(t, SpanStream, span.name)
+	require.True(t, span.ended)
+	require.Equal(t, 1, span.attribute(AttributeStreamReconnect))
+	require.Equal(t, "closed", span.attribute(AttributeStreamStatus))
+	require.Equal(t, http.StatusOK, span.attribute(AttributeStatusCode))
+
+	for _, request := range server.received() {
+		require.Equal(t, span.ctx.Traceparent(), request.Header.Get(headerTraceparent))
+	}
+}
@@ -211,7 +211,7 @@ func (arr ArrayV) At(field Field) FieldValue { return field.get(arr) }
 type NullV struct{}
 
 // Get implements the Value interface by decoding the underlying value to a either a NullV or a nil pointer.
-func (null NullV) Get(i interface{}) error { return nil }
+func (null NullV) Get(i interface{}) error { return decodeNull(i) }
 
 // At implements the Value interface by returning an invalid field since NullV is not traversable.
 func (null NullV) At(field Field) FieldValue { return field.get(null) }
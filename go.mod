@@ -5,4 +5,11 @@ require (
 	golang.org/x/net v0.0.0-20201224014010-6772e930b67b
 )
 
-go 1.16
\ No newline at end of file
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.3.3 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
+
+go 1.18